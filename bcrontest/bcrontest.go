@@ -0,0 +1,252 @@
+// Package bcrontest runs a better_cron scheduler on virtual time and records
+// every run, so tests can assert on schedules without sleeping.
+package bcrontest
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"cron_test/better_cron"
+)
+
+// settleTimeout bounds how long Advance waits in real time for jobs to
+// finish before failing the test
+const settleTimeout = 10 * time.Second
+
+// Run is the record of one finished run
+type Run struct {
+	Job     string
+	RunID   string
+	Start   time.Time
+	End     time.Time
+	Status  better_cron.JobStatus
+	Err     error
+	Attempt int
+}
+
+// Recorder owns a scheduler driven by a fake clock
+type Recorder struct {
+	Cron  *better_cron.EnhancedCron
+	Clock *better_cron.FakeClock
+
+	t    TB
+	mu   sync.Mutex
+	runs []Run
+	// fires counts fires started by Advance; outcomes counts the skipped
+	// and terminal events they have produced so far
+	fires    int
+	outcomes int
+	// expiring holds jobs whose end time was reached by Advance and whose
+	// EventJobExpired is still outstanding
+	expiring map[string]bool
+}
+
+// New creates a recorder whose clock starts at start, failing t if runs do
+// not settle. Extra options are passed to the scheduler; add jobs to r.Cron
+// and then call Start.
+func New(t TB, start time.Time, opts ...better_cron.Option) *Recorder {
+	r := &Recorder{Clock: better_cron.NewFakeClock(start), t: t, expiring: make(map[string]bool)}
+	opts = append(opts, better_cron.WithClock(r.Clock), better_cron.WithEventSink(better_cron.EventSinkFunc(r.handle)))
+	r.Cron = better_cron.NewEnhancedCron(opts...)
+	return r
+}
+
+// Start starts the scheduler and waits for any run-on-start jobs
+func (r *Recorder) Start() {
+	r.t.Helper()
+	fires := 0
+	for _, job := range r.Cron.ListJobs() {
+		if job.RunOnStart {
+			fires++
+		}
+	}
+	r.Cron.Start()
+	r.settle(fires)
+}
+
+// Close shuts the scheduler down, cancelling any runs still in flight
+func (r *Recorder) Close() error {
+	return r.Cron.Shutdown(context.Background(), better_cron.ShutdownAbort)
+}
+
+// handle records terminal events and counts every fire's outcome
+func (r *Recorder) handle(event better_cron.JobEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch event.Type {
+	case better_cron.EventJobSkipped:
+		r.outcomes++
+		// A holiday fire moved to the next business day has an outcome of
+		// its own still to come
+		if strings.HasPrefix(event.Reason, "holiday, deferred to ") {
+			r.fires++
+		}
+	case better_cron.EventJobExpired:
+		if r.expiring[event.Job] {
+			delete(r.expiring, event.Job)
+			r.outcomes++
+		}
+	case better_cron.EventJobCompleted, better_cron.EventJobFailed, better_cron.EventJobCancelled:
+		r.outcomes++
+		m := event.Metadata
+		r.runs = append(r.runs, Run{
+			Job:     event.Job,
+			RunID:   m.RunID,
+			Start:   m.StartTime,
+			End:     m.EndTime,
+			Status:  m.Status,
+			Err:     m.Error,
+			Attempt: m.Attempt,
+		})
+	}
+}
+
+// Advance moves virtual time forward by d
+func (r *Recorder) Advance(d time.Duration) {
+	r.t.Helper()
+	r.AdvanceTo(r.Clock.Now().Add(d))
+}
+
+// AdvanceTo moves virtual time to t one timer at a time, so every fire,
+// retry backoff and jitter delay on the way happens at its own time, and
+// waits for the runs each step starts
+func (r *Recorder) AdvanceTo(t time.Time) {
+	r.t.Helper()
+	for {
+		r.settle(0)
+		next, ok := r.Clock.NextDeadline()
+		if !ok || next.After(t) {
+			r.Clock.Set(t)
+			r.settle(r.dueFires(t))
+			return
+		}
+		fires := r.dueFires(next)
+		r.Clock.Set(next)
+		r.settle(fires)
+	}
+}
+
+// dueFires counts the jobs whose next fire or end time is at or before t
+func (r *Recorder) dueFires(t time.Time) int {
+	n := 0
+	for _, job := range r.Cron.ListJobs() {
+		if !job.Next.IsZero() && !job.Next.After(t) {
+			n++
+		}
+		if !job.EndAt.IsZero() && !job.EndAt.After(t) {
+			r.mu.Lock()
+			r.expiring[job.Name] = true
+			r.mu.Unlock()
+			n++
+		}
+	}
+	return n
+}
+
+// settle adds fires to the expected total and waits until every fire so far
+// has an outcome and no run is active, or until a run is sleeping on
+// virtual time. It fails the test if that takes longer than settleTimeout.
+func (r *Recorder) settle(fires int) {
+	r.t.Helper()
+	r.mu.Lock()
+	r.fires += fires
+	r.mu.Unlock()
+
+	deadline := time.Now().Add(settleTimeout)
+	for time.Now().Before(deadline) {
+		active := len(r.Cron.GetActiveJobs())
+		r.mu.Lock()
+		done := r.outcomes >= r.fires && active == 0
+		r.mu.Unlock()
+
+		// While jobs remain, the scheduler holds one timer for its next fire
+		// and must have re-armed it before time moves on. Any further timers
+		// belong to runs sleeping on virtual time, which only Advance wakes.
+		own := 0
+		if r.scheduled() {
+			own = 1
+		}
+		pending := r.Clock.Pending()
+		if pending >= own && (done || pending > own) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	r.mu.Lock()
+	pending := r.fires - r.outcomes
+	r.mu.Unlock()
+	r.t.Fatalf("bcrontest: %d fires and %d runs did not settle within %v at %s",
+		pending, len(r.Cron.GetActiveJobs()), settleTimeout, r.Clock.Now().Format(time.RFC3339))
+}
+
+// scheduled reports whether any job has a fire or end time ahead
+func (r *Recorder) scheduled() bool {
+	for _, job := range r.Cron.ListJobs() {
+		if !job.Next.IsZero() || !job.EndAt.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// Runs returns every recorded run in completion order
+func (r *Recorder) Runs() []Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Run(nil), r.runs...)
+}
+
+// RunsOf returns the recorded runs of one job
+func (r *Recorder) RunsOf(name string) []Run {
+	var runs []Run
+	for _, run := range r.Runs() {
+		if run.Job == name {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// Count returns how many runs of the job started in [from, to]
+func (r *Recorder) Count(name string, from, to time.Time) int {
+	n := 0
+	for _, run := range r.RunsOf(name) {
+		if !run.Start.Before(from) && !run.Start.After(to) {
+			n++
+		}
+	}
+	return n
+}
+
+// TB is the part of testing.TB the recorder uses
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// ExpectRuns fails the test unless the job started exactly n runs in
+// [from, to]
+func (r *Recorder) ExpectRuns(t TB, name string, n int, from, to time.Time) {
+	t.Helper()
+	if got := r.Count(name, from, to); got != n {
+		t.Errorf("job %s ran %d times between %s and %s, want %d",
+			name, got, from.Format(time.RFC3339), to.Format(time.RFC3339), n)
+	}
+}
+
+// ExpectStatus fails the test unless every recorded run of the job ended
+// with the given status
+func (r *Recorder) ExpectStatus(t TB, name string, status better_cron.JobStatus) {
+	t.Helper()
+	for _, run := range r.RunsOf(name) {
+		if run.Status != status {
+			t.Errorf("job %s run %s at %s ended %s, want %s",
+				name, run.RunID, run.Start.Format(time.RFC3339), run.Status, status)
+		}
+	}
+}
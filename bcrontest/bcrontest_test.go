@@ -0,0 +1,169 @@
+package bcrontest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cron_test/better_cron"
+)
+
+var nine = time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+// fakeTB collects the failures of an expectation
+type fakeTB struct {
+	errors []string
+}
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeTB) Fatalf(format string, args ...interface{}) {
+	t.Errorf(format, args...)
+}
+
+func noop(context.Context) error { return nil }
+
+func TestRecorderRecordsRuns(t *testing.T) {
+	r := New(t, nine)
+	defer r.Close()
+
+	if _, err := r.Cron.AddJob("@every 5m", better_cron.ErrorFuncJob(noop), "my-job"); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+	r.AdvanceTo(nine.Add(15 * time.Minute))
+
+	r.ExpectRuns(t, "my-job", 3, nine, nine.Add(15*time.Minute))
+	r.ExpectStatus(t, "my-job", better_cron.StatusCompleted)
+	for i, run := range r.RunsOf("my-job") {
+		if want := nine.Add(time.Duration(i+1) * 5 * time.Minute); !run.Start.Equal(want) {
+			t.Errorf("run %d started at %v, want %v", i+1, run.Start, want)
+		}
+		if run.RunID == "" {
+			t.Errorf("run %d has no run ID", i+1)
+		}
+	}
+}
+
+func TestRecorderCount(t *testing.T) {
+	r := New(t, nine)
+	defer r.Close()
+
+	r.Cron.AddJob("0 */5 * * * *", better_cron.ErrorFuncJob(noop), "a")
+	r.Cron.AddJob("0 0 * * * *", better_cron.ErrorFuncJob(noop), "b")
+	r.Start()
+	r.Advance(2 * time.Hour)
+
+	if n := r.Count("a", nine, nine.Add(time.Hour)); n != 12 {
+		t.Errorf("a ran %d times in the first hour, want 12", n)
+	}
+	if n := r.Count("b", nine, nine.Add(2*time.Hour)); n != 2 {
+		t.Errorf("b ran %d times, want 2", n)
+	}
+	if n := len(r.Runs()); n != 26 {
+		t.Errorf("%d runs recorded in total, want 26", n)
+	}
+}
+
+func TestRecorderFailedRuns(t *testing.T) {
+	r := New(t, nine)
+	defer r.Close()
+
+	failure := errors.New("boom")
+	r.Cron.AddJob("@every 1m", better_cron.ErrorFuncJob(func(context.Context) error { return failure }), "broken")
+	r.Start()
+	r.Advance(2 * time.Minute)
+
+	runs := r.RunsOf("broken")
+	if len(runs) != 2 {
+		t.Fatalf("%d runs recorded, want 2", len(runs))
+	}
+	for _, run := range runs {
+		if run.Status != better_cron.StatusFailed || !errors.Is(run.Err, failure) {
+			t.Errorf("run %s ended %s with %v, want failed with %v", run.RunID, run.Status, run.Err, failure)
+		}
+	}
+}
+
+func TestRecorderRunOnStart(t *testing.T) {
+	r := New(t, nine)
+	defer r.Close()
+
+	r.Cron.AddJob("@every 1h", better_cron.ErrorFuncJob(noop), "eager", better_cron.WithRunOnStart())
+	r.Start()
+	r.ExpectRuns(t, "eager", 1, nine, nine)
+}
+
+func TestRecorderEndAt(t *testing.T) {
+	r := New(t, nine)
+	defer r.Close()
+
+	r.Cron.AddJob("@every 10m", better_cron.ErrorFuncJob(noop), "temporary", better_cron.WithEndAt(nine.Add(25*time.Minute)))
+	r.Start()
+	r.Advance(time.Hour)
+
+	r.ExpectRuns(t, "temporary", 2, nine, nine.Add(time.Hour))
+	if jobs := r.Cron.ListJobs(); len(jobs) != 0 {
+		t.Errorf("%d jobs still registered after the end time, want 0", len(jobs))
+	}
+}
+
+func TestRecorderVirtualSleep(t *testing.T) {
+	r := New(t, nine)
+	defer r.Close()
+
+	// The run sleeps on virtual time, so it only ends once time moves on
+	job := func(ctx context.Context) error {
+		timer := r.Clock.NewTimer(3 * time.Minute)
+		defer timer.Stop()
+		<-timer.C()
+		return nil
+	}
+	r.Cron.AddJob("0 0 * * * *", better_cron.ErrorFuncJob(job), "long")
+	r.Start()
+
+	r.Advance(time.Hour + time.Minute)
+	if n := len(r.RunsOf("long")); n != 0 {
+		t.Fatalf("%d runs finished while the run sleeps, want 0", n)
+	}
+	r.Advance(2 * time.Minute)
+	runs := r.RunsOf("long")
+	if len(runs) != 1 {
+		t.Fatalf("%d runs finished, want 1", len(runs))
+	}
+	if got := runs[0].End.Sub(runs[0].Start); got != 3*time.Minute {
+		t.Errorf("run took %v of virtual time, want 3m", got)
+	}
+}
+
+func TestExpectationsReportFailures(t *testing.T) {
+	r := New(t, nine)
+	defer r.Close()
+
+	r.Cron.AddJob("@every 5m", better_cron.ErrorFuncJob(noop), "my-job")
+	r.Start()
+	r.Advance(15 * time.Minute)
+
+	tb := &fakeTB{}
+	r.ExpectRuns(tb, "my-job", 2, nine, nine.Add(15*time.Minute))
+	r.ExpectStatus(tb, "my-job", better_cron.StatusFailed)
+	if len(tb.errors) != 4 {
+		t.Fatalf("got %d failures, want 4: %q", len(tb.errors), tb.errors)
+	}
+	if want := "job my-job ran 3 times between 2026-03-10T09:00:00Z and 2026-03-10T09:15:00Z, want 2"; tb.errors[0] != want {
+		t.Errorf("failure = %q, want %q", tb.errors[0], want)
+	}
+
+	tb = &fakeTB{}
+	r.ExpectRuns(tb, "my-job", 3, nine, nine.Add(15*time.Minute))
+	r.ExpectRuns(tb, "other", 0, nine, nine.Add(15*time.Minute))
+	if len(tb.errors) != 0 {
+		t.Errorf("met expectations failed: %q", tb.errors)
+	}
+}
@@ -0,0 +1,97 @@
+package better_cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// actionPrefix is the store key prefix of admin actions
+const actionPrefix = "actions/"
+
+// maxMemoryActions bounds the admin actions kept without a store
+const maxMemoryActions = 1000
+
+// AdminAction is one mutating operation performed on the scheduler, such as
+// a manual trigger through the admin API or a reload of a job file. Unlike
+// the audit log it records who poked the scheduler, not what jobs did.
+type AdminAction struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	Action string    `json:"action"`
+	// Target is the job, run or component acted on
+	Target string            `json:"target,omitempty"`
+	Params map[string]string `json:"params,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// actionLog keeps admin actions in the store, or in memory without one
+type actionLog struct {
+	seq    atomic.Uint64
+	mu     sync.Mutex
+	memory []AdminAction
+}
+
+// recordAction logs a mutating operation and keeps it for AdminActions
+func (ec *EnhancedCron) recordAction(action AdminAction, err error) {
+	if ec.readOnly {
+		return
+	}
+	action.Time = ec.clock.Now()
+	if err != nil {
+		action.Error = err.Error()
+	}
+	ec.logger.Info("admin action %s on %s by %s", action.Action, action.Target, action.Actor)
+
+	if ec.store == nil {
+		ec.actions.mu.Lock()
+		ec.actions.memory = append(ec.actions.memory, action)
+		if over := len(ec.actions.memory) - maxMemoryActions; over > 0 {
+			ec.actions.memory = append(ec.actions.memory[:0:0], ec.actions.memory[over:]...)
+		}
+		ec.actions.mu.Unlock()
+		return
+	}
+
+	data, err := json.Marshal(action)
+	if err == nil {
+		key := fmt.Sprintf("%s%020d-%010d", actionPrefix, action.Time.UnixNano(), ec.actions.seq.Add(1))
+		err = ec.store.Put(key, data)
+	}
+	if err != nil {
+		ec.logger.Error("failed to record admin action %s: %v", action.Action, err)
+	}
+}
+
+// AdminActions returns the recorded admin actions, oldest first. Without a
+// store only the last 1000 actions of this process are kept.
+func (ec *EnhancedCron) AdminActions() ([]AdminAction, error) {
+	if ec.store == nil {
+		ec.actions.mu.Lock()
+		defer ec.actions.mu.Unlock()
+		return append([]AdminAction(nil), ec.actions.memory...), nil
+	}
+
+	keys, err := ec.store.List(actionPrefix)
+	if err != nil {
+		return nil, err
+	}
+	actions := make([]AdminAction, 0, len(keys))
+	for _, key := range keys {
+		data, ok, err := ec.store.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		var action AdminAction
+		if err := json.Unmarshal(data, &action); err != nil {
+			return nil, fmt.Errorf("decode admin action %s: %w", key, err)
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
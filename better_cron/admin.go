@@ -0,0 +1,379 @@
+package better_cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// LevelController is implemented by loggers whose levels can change at
+// runtime, such as custom_logger.Logger. The admin API uses it to turn
+// debugging on per component without a restart.
+type LevelController interface {
+	ComponentLevels() map[string]string
+	SetComponentLevel(component, level string) error
+}
+
+// adminConfig holds the settings collected from AdminOptions
+type adminConfig struct {
+	levels   LevelController
+	auth     Authenticator
+	readOnly bool
+}
+
+// AdminOption represents a configuration option for the admin API
+type AdminOption func(*adminConfig)
+
+// WithLevelController sets the logger whose levels the admin API manages.
+// By default it is the scheduler's logger, if that is a LevelController.
+func WithLevelController(levels LevelController) AdminOption {
+	return func(cfg *adminConfig) {
+		cfg.levels = levels
+	}
+}
+
+// AdminHandler returns an http.Handler serving the admin API as JSON, with
+// the role each endpoint requires:
+//
+//	GET    /jobs                   viewer    registered jobs, ?namespace= filters them
+//	GET    /jobs/{name}/history    viewer    recorded runs of a job
+//	GET    /jobs/{name}/versions   viewer    changes to a job's definition
+//	POST   /jobs/{name}/trigger    operator  run a job now
+//	POST   /jobs/{name}/pause      operator  pause a job until it is resumed
+//	POST   /jobs/{name}/resume     operator  resume a paused job
+//	DELETE /jobs/{name}            admin     remove a job
+//	GET    /analysis               viewer    hotspots and dependency conflicts, ?horizon=&threshold=
+//	GET    /runs                   viewer    runs in flight, including orphaned ones
+//	POST   /runs/{id}/cancel       operator  cancel a run in flight
+//	GET    /loglevels              viewer    log level of every component
+//	PUT    /loglevels/{component}  operator  set a level, body {"level": "DEBUG"}
+//	POST   /reload                 admin     reload every job file loaded so far
+//	GET    /actions                operator  mutating actions and who performed them
+//
+// Every mutating request is recorded as an AdminAction.
+// Mount it under a prefix with http.StripPrefix.
+func (ec *EnhancedCron) AdminHandler(opts ...AdminOption) http.Handler {
+	cfg := &adminConfig{}
+	if levels, ok := ec.logger.(LevelController); ok {
+		cfg.levels = levels
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	a := &admin{ec: ec, cfg: cfg}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /jobs", a.require(RoleViewer, a.listJobs))
+	mux.HandleFunc("GET /jobs/{name}/history", a.require(RoleViewer, a.jobHistory))
+	mux.HandleFunc("GET /jobs/{name}/versions", a.require(RoleViewer, a.jobVersions))
+	mux.HandleFunc("POST /jobs/{name}/trigger", a.require(RoleOperator, a.triggerJob))
+	mux.HandleFunc("POST /jobs/{name}/pause", a.require(RoleOperator, a.pauseJob))
+	mux.HandleFunc("POST /jobs/{name}/resume", a.require(RoleOperator, a.resumeJob))
+	mux.HandleFunc("DELETE /jobs/{name}", a.require(RoleAdmin, a.removeJob))
+	mux.HandleFunc("GET /analysis", a.require(RoleViewer, a.analyzeSchedules))
+	mux.HandleFunc("GET /runs", a.require(RoleViewer, a.listRuns))
+	mux.HandleFunc("POST /runs/{id}/cancel", a.require(RoleOperator, a.cancelRun))
+	mux.HandleFunc("GET /loglevels", a.require(RoleViewer, a.logLevels))
+	mux.HandleFunc("PUT /loglevels/{component}", a.require(RoleOperator, a.setLogLevel))
+	mux.HandleFunc("POST /reload", a.require(RoleAdmin, a.reload))
+	mux.HandleFunc("GET /actions", a.require(RoleOperator, a.listActions))
+	return mux
+}
+
+// admin serves the admin API of one scheduler
+type admin struct {
+	ec  *EnhancedCron
+	cfg *adminConfig
+}
+
+// jobView is the JSON form of a JobInfo
+type jobView struct {
+	Name     string    `json:"name"`
+	Spec     string    `json:"spec"`
+	Tags     []string  `json:"tags,omitempty"`
+	Timezone string    `json:"timezone,omitempty"`
+	Next     time.Time `json:"next,omitzero"`
+	Prev     time.Time `json:"prev,omitzero"`
+	EndAt    time.Time `json:"end_at,omitzero"`
+	// Namespace is set for jobs outside the default namespace
+	Namespace string `json:"namespace,omitempty"`
+	Paused    bool   `json:"paused,omitempty"`
+	// Description is the schedule in plain English
+	Description string `json:"description,omitempty"`
+}
+
+// runView is the JSON form of a JobMetadata
+type runView struct {
+	Job       string        `json:"job"`
+	RunID     string        `json:"run_id"`
+	Status    string        `json:"status"`
+	Attempt   int           `json:"attempt,omitempty"`
+	Trigger   string        `json:"trigger,omitempty"`
+	Scheduled time.Time     `json:"scheduled_time,omitzero"`
+	StartTime time.Time     `json:"start_time"`
+	EndTime   time.Time     `json:"end_time,omitzero"`
+	Error     string        `json:"error,omitempty"`
+	Result    interface{}   `json:"result,omitempty"`
+	Resources *usageView    `json:"resources,omitempty"`
+	Orphaned  bool          `json:"orphaned,omitempty"`
+	Progress  *progressView `json:"progress,omitempty"`
+
+	Params      map[string]string `json:"params,omitempty"`
+	Fingerprint string            `json:"fingerprint,omitempty"`
+}
+
+// progressView is the JSON form of a Progress
+type progressView struct {
+	Percent float64   `json:"percent"`
+	Step    string    `json:"step,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// usageView is the JSON form of a ResourceUsage
+type usageView struct {
+	CPUSeconds     float64 `json:"cpu_seconds"`
+	AllocBytes     uint64  `json:"alloc_bytes"`
+	PeakGoroutines int     `json:"peak_goroutines"`
+}
+
+func newRunView(m JobMetadata) runView {
+	view := runView{
+		Job:       m.Name,
+		RunID:     m.RunID,
+		Status:    m.Status.String(),
+		Attempt:   m.Attempt,
+		Trigger:   m.Trigger,
+		Scheduled: m.ScheduledTime,
+		StartTime: m.StartTime,
+		EndTime:   m.EndTime,
+		Result:    m.Result,
+		Orphaned:  m.Orphaned,
+		Params:    m.Params,
+
+		Fingerprint: m.Fingerprint,
+	}
+	if m.Error != nil {
+		view.Error = m.Error.Error()
+	}
+	if p := m.Progress; !p.Updated.IsZero() {
+		view.Progress = &progressView{Percent: p.Percent, Step: p.Step, Updated: p.Updated}
+	}
+	if u := m.Resources; u != (ResourceUsage{}) {
+		view.Resources = &usageView{CPUSeconds: u.CPUTime.Seconds(), AllocBytes: u.AllocBytes, PeakGoroutines: u.PeakGoroutines}
+	}
+	return view
+}
+
+func (a *admin) listJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := a.ec.ListJobs()
+	if r.URL.Query().Has("namespace") {
+		jobs = a.ec.Namespace(r.URL.Query().Get("namespace")).ListJobs()
+	}
+	views := make([]jobView, 0, len(jobs))
+	for _, job := range jobs {
+		view := jobView{Name: job.Name, Spec: job.Spec, Tags: job.Tags, Next: job.Next, Prev: job.Prev, EndAt: job.EndAt, Namespace: job.Namespace, Paused: job.Paused, Description: job.Description}
+		if job.Timezone != nil {
+			view.Timezone = job.Timezone.String()
+		}
+		views = append(views, view)
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (a *admin) jobHistory(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	history, ok := a.ec.GetJobHistory(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %q not found", name))
+		return
+	}
+	views := make([]runView, 0, len(history))
+	for _, m := range history {
+		views = append(views, newRunView(m))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (a *admin) jobVersions(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	versions, err := a.ec.GetJobVersions(name)
+	if err != nil {
+		writeError(w, http.StatusNotImplemented, err)
+		return
+	}
+	if len(versions) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %q has no recorded versions", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (a *admin) triggerJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := a.ec.triggerJob(name, caller(r))
+	a.ec.recordAction(AdminAction{Actor: caller(r), Action: "trigger", Target: name}, err)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"job": name, "status": "triggered"})
+}
+
+func (a *admin) pauseJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := a.ec.pauseJob(name, caller(r), "paused")
+	a.ec.recordAction(AdminAction{Actor: caller(r), Action: "pause", Target: name}, err)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"job": name, "status": "paused"})
+}
+
+func (a *admin) resumeJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := a.ec.resumeJob(name, caller(r))
+	a.ec.recordAction(AdminAction{Actor: caller(r), Action: "resume", Target: name}, err)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"job": name, "status": "resumed"})
+}
+
+func (a *admin) removeJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := a.ec.removeJob(name, caller(r))
+	a.ec.recordAction(AdminAction{Actor: caller(r), Action: "remove", Target: name}, err)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"job": name, "status": "removed"})
+}
+
+func (a *admin) cancelRun(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	err := a.ec.CancelRun(id)
+	a.ec.recordAction(AdminAction{Actor: caller(r), Action: "cancel", Target: id}, err)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"run_id": id, "status": "cancelling"})
+}
+
+// analyzeSchedules reports hotspots and dependency conflicts, over the
+// "horizon" query parameter (default 24h) with hotspots of at least
+// "threshold" jobs (default 3)
+func (a *admin) analyzeSchedules(w http.ResponseWriter, r *http.Request) {
+	horizon, threshold := 24*time.Hour, 3
+	if v := r.URL.Query().Get("horizon"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid horizon: %w", err))
+			return
+		}
+		horizon = d
+	}
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid threshold: %w", err))
+			return
+		}
+		threshold = n
+	}
+
+	analysis, err := a.ec.AnalyzeSchedules(horizon, threshold)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, analysis)
+}
+
+func (a *admin) listRuns(w http.ResponseWriter, r *http.Request) {
+	runs := append(a.ec.GetActiveJobs(), a.ec.GetOrphanedJobs()...)
+	views := make([]runView, 0, len(runs))
+	for _, m := range runs {
+		views = append(views, newRunView(*m))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (a *admin) logLevels(w http.ResponseWriter, r *http.Request) {
+	if a.cfg.levels == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("logger does not support runtime levels"))
+		return
+	}
+	writeJSON(w, http.StatusOK, a.cfg.levels.ComponentLevels())
+}
+
+func (a *admin) setLogLevel(w http.ResponseWriter, r *http.Request) {
+	if a.cfg.levels == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("logger does not support runtime levels"))
+		return
+	}
+
+	var body struct {
+		Level string `json:"level"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+		return
+	}
+	component := r.PathValue("component")
+	err := a.cfg.levels.SetComponentLevel(component, body.Level)
+	a.ec.recordAction(AdminAction{Actor: caller(r), Action: "set_log_level", Target: component, Params: map[string]string{"level": body.Level}}, err)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.cfg.levels.ComponentLevels())
+}
+
+func (a *admin) reload(w http.ResponseWriter, r *http.Request) {
+	a.ec.configMu.Lock()
+	paths := make([]string, 0, len(a.ec.configDefs))
+	for path := range a.ec.configDefs {
+		paths = append(paths, path)
+	}
+	a.ec.configMu.Unlock()
+	sort.Strings(paths)
+
+	results := make(map[string]string, len(paths))
+	status := http.StatusOK
+	for _, path := range paths {
+		results[path] = "reloaded"
+		if err := a.ec.loadJobsFile(path, caller(r)); err != nil {
+			results[path] = err.Error()
+			status = http.StatusUnprocessableEntity
+		}
+	}
+	writeJSON(w, status, results)
+}
+
+func (a *admin) listActions(w http.ResponseWriter, r *http.Request) {
+	actions, err := a.ec.AdminActions()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, actions)
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,66 @@
+package better_cron
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// AdminTLSConfig builds the server TLS settings for serving the admin API,
+// the only control channel the scheduler serves; it has no gRPC control
+// plane or remote workers. If clientCAFile is set, clients must present a
+// certificate signed by one of its CAs, so only holders of one can reach
+// the API at all; combine it with WithAuthenticator to also check a token
+// on every call.
+func AdminTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load admin certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CAs: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", clientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// CertAuthenticator identifies callers by the common name of their verified
+// client certificate, granting the role mapped to it. It needs a server
+// set up with AdminTLSConfig and a client CA file.
+type CertAuthenticator struct {
+	roles map[string]Role
+}
+
+// NewCertAuthenticator creates an authenticator granting each certificate
+// common name its role
+func NewCertAuthenticator(roles map[string]Role) *CertAuthenticator {
+	return &CertAuthenticator{roles: roles}
+}
+
+// Authenticate looks up the common name of the request's client certificate
+func (a *CertAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return Principal{}, ErrUnauthenticated
+	}
+	name := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	role, ok := a.roles[name]
+	if !ok {
+		return Principal{}, fmt.Errorf("%w: certificate %q has no role", ErrUnauthenticated, name)
+	}
+	return Principal{Name: name, Role: role}, nil
+}
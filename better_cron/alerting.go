@@ -0,0 +1,330 @@
+package better_cron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Incident describes an alert opened or resolved for a job
+type Incident struct {
+	Key     string
+	Job     string
+	Summary string
+	Details string
+}
+
+// IncidentProvider opens and resolves incidents in an external alerting system
+type IncidentProvider interface {
+	Trigger(ctx context.Context, incident Incident) error
+	Resolve(ctx context.Context, incident Incident) error
+}
+
+// AlertNotifier is an EventSink that opens an incident when a tagged job fails
+// repeatedly or misses an SLA deadline, and resolves it once a subsequent run
+// succeeds. Provider calls are made in order by a worker of its own, so
+// a slow provider does not hold up runs; Close stops the worker.
+type AlertNotifier struct {
+	provider  IncidentProvider
+	tag       string
+	threshold int
+	timeout   time.Duration
+	queueSize int
+	logger    Logger
+
+	mu       sync.Mutex
+	failures map[string]int
+	open     map[string]bool
+	closed   bool
+
+	queue chan alertCall
+	done  chan struct{}
+}
+
+// alertCall is a provider call waiting for the worker
+type alertCall struct {
+	incident Incident
+	resolve  bool
+}
+
+// AlertOption represents configuration options for AlertNotifier
+type AlertOption func(*AlertNotifier)
+
+// WithAlertTag sets the tag a job must carry to be alerted on
+func WithAlertTag(tag string) AlertOption {
+	return func(n *AlertNotifier) {
+		n.tag = tag
+	}
+}
+
+// WithAlertThreshold sets how many consecutive failures open an incident
+func WithAlertThreshold(threshold int) AlertOption {
+	return func(n *AlertNotifier) {
+		n.threshold = threshold
+	}
+}
+
+// WithAlertTimeout bounds each call made to the provider
+func WithAlertTimeout(timeout time.Duration) AlertOption {
+	return func(n *AlertNotifier) {
+		n.timeout = timeout
+	}
+}
+
+// WithAlertQueue sets how many provider calls may wait for delivery
+// (default 100). Incidents that do not fit are dropped and logged, and
+// opened or resolved again on the job's next event.
+func WithAlertQueue(size int) AlertOption {
+	return func(n *AlertNotifier) {
+		n.queueSize = size
+	}
+}
+
+// WithAlertLogger sets a logger for provider errors
+func WithAlertLogger(logger Logger) AlertOption {
+	return func(n *AlertNotifier) {
+		n.logger = logger
+	}
+}
+
+// NewAlertNotifier creates a notifier for jobs tagged "critical" and starts
+// its delivery worker
+func NewAlertNotifier(provider IncidentProvider, opts ...AlertOption) *AlertNotifier {
+	n := &AlertNotifier{
+		provider:  provider,
+		tag:       "critical",
+		threshold: 3,
+		timeout:   10 * time.Second,
+		queueSize: 100,
+		failures:  make(map[string]int),
+		open:      make(map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+	if n.queueSize < 1 {
+		n.queueSize = 1
+	}
+
+	n.queue = make(chan alertCall, n.queueSize)
+	n.done = make(chan struct{})
+	go n.deliver()
+	return n
+}
+
+// HandleEvent tracks consecutive failures and queues incidents to trigger
+// or resolve
+func (n *AlertNotifier) HandleEvent(event JobEvent) {
+	if !event.HasTag(n.tag) {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var trigger, resolve bool
+	var summary string
+	switch event.Type {
+	case EventSLAMissed:
+		if !n.open[event.Job] {
+			n.open[event.Job] = true
+			trigger = true
+		}
+		summary = fmt.Sprintf("job %s missed its SLA deadline", event.Job)
+	case EventJobFailed:
+		n.failures[event.Job]++
+		if n.failures[event.Job] >= n.threshold && !n.open[event.Job] {
+			n.open[event.Job] = true
+			trigger = true
+		}
+	case EventJobCompleted:
+		n.failures[event.Job] = 0
+		if n.open[event.Job] {
+			delete(n.open, event.Job)
+			resolve = true
+		}
+	}
+	if (!trigger && !resolve) || n.closed {
+		return
+	}
+
+	if summary == "" {
+		summary = fmt.Sprintf("job %s failed %d times in a row", event.Job, n.failures[event.Job])
+	}
+	if resolve {
+		summary = fmt.Sprintf("job %s recovered", event.Job)
+	}
+	incident := Incident{
+		Key:     "better_cron/" + event.Job,
+		Job:     event.Job,
+		Summary: summary,
+		Details: event.Reason,
+	}
+	if event.Metadata.Error != nil {
+		incident.Details = event.Metadata.Error.Error()
+	}
+
+	select {
+	case n.queue <- alertCall{incident: incident, resolve: resolve}:
+	default:
+		// Undo the change so the job's next event tries again
+		if trigger {
+			delete(n.open, event.Job)
+		} else {
+			n.open[event.Job] = true
+		}
+		if n.logger != nil {
+			n.logger.Error("alerting for job %s dropped: queue full", event.Job)
+		}
+	}
+}
+
+// Close stops accepting incidents and waits for the queued ones to be
+// delivered
+func (n *AlertNotifier) Close() error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+	<-n.done
+	return nil
+}
+
+// deliver makes the queued provider calls in order
+func (n *AlertNotifier) deliver() {
+	defer close(n.done)
+	for call := range n.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+		var err error
+		if call.resolve {
+			err = n.provider.Resolve(ctx, call.incident)
+		} else {
+			err = n.provider.Trigger(ctx, call.incident)
+		}
+		cancel()
+		if err != nil && n.logger != nil {
+			n.logger.Error("alerting for job %s failed: %v", call.incident.Job, err)
+		}
+	}
+}
+
+// PagerDutyProvider sends incidents to the PagerDuty Events API v2
+type PagerDutyProvider struct {
+	RoutingKey string
+	Source     string
+	URL        string
+	Client     *http.Client
+}
+
+// Trigger opens a PagerDuty incident deduplicated by the incident key
+func (p *PagerDutyProvider) Trigger(ctx context.Context, incident Incident) error {
+	source := p.Source
+	if source == "" {
+		source = "better_cron"
+	}
+	return p.send(ctx, map[string]interface{}{
+		"routing_key":  p.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    incident.Key,
+		"payload": map[string]interface{}{
+			"summary":        incident.Summary,
+			"source":         source,
+			"severity":       "critical",
+			"custom_details": map[string]string{"job": incident.Job, "error": incident.Details},
+		},
+	})
+}
+
+// Resolve resolves the PagerDuty incident with the same key
+func (p *PagerDutyProvider) Resolve(ctx context.Context, incident Incident) error {
+	return p.send(ctx, map[string]interface{}{
+		"routing_key":  p.RoutingKey,
+		"event_action": "resolve",
+		"dedup_key":    incident.Key,
+	})
+}
+
+func (p *PagerDutyProvider) send(ctx context.Context, body interface{}) error {
+	endpoint := p.URL
+	if endpoint == "" {
+		endpoint = "https://events.pagerduty.com/v2/enqueue"
+	}
+	return postJSON(ctx, p.Client, endpoint, nil, body)
+}
+
+// OpsgenieProvider sends incidents to the Opsgenie Alert API
+type OpsgenieProvider struct {
+	APIKey string
+	URL    string
+	Client *http.Client
+}
+
+// Trigger creates an Opsgenie alert aliased by the incident key
+func (p *OpsgenieProvider) Trigger(ctx context.Context, incident Incident) error {
+	return postJSON(ctx, p.Client, p.baseURL(), p.headers(), map[string]interface{}{
+		"message":     incident.Summary,
+		"alias":       incident.Key,
+		"description": incident.Details,
+		"priority":    "P1",
+		"details":     map[string]string{"job": incident.Job},
+	})
+}
+
+// Resolve closes the Opsgenie alert with the same alias
+func (p *OpsgenieProvider) Resolve(ctx context.Context, incident Incident) error {
+	endpoint := p.baseURL() + "/" + url.PathEscape(incident.Key) + "/close?identifierType=alias"
+	return postJSON(ctx, p.Client, endpoint, p.headers(), map[string]interface{}{
+		"note": incident.Summary,
+	})
+}
+
+func (p *OpsgenieProvider) baseURL() string {
+	if p.URL != "" {
+		return p.URL
+	}
+	return "https://api.opsgenie.com/v2/alerts"
+}
+
+func (p *OpsgenieProvider) headers() map[string]string {
+	return map[string]string{"Authorization": "GenieKey " + p.APIKey}
+}
+
+// postJSON posts body as JSON and treats any non-2xx response as an error
+func postJSON(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, body interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, endpoint)
+	}
+	return nil
+}
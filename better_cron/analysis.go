@@ -0,0 +1,204 @@
+package better_cron
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// WithUpstream declares the jobs whose output this job consumes, so
+// AnalyzeSchedules can flag it when it is scheduled before them. It does not
+// hold runs back; use WithPrecondition for that.
+func WithUpstream(names ...string) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.upstreams = append(cfg.upstreams, names...)
+	}
+}
+
+// ScheduleAnalysis reports the problems AnalyzeSchedules found between From
+// and To
+type ScheduleAnalysis struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Hotspots are the instants where at least the threshold of jobs fire
+	// together, in time order
+	Hotspots  []Hotspot            `json:"hotspots"`
+	Conflicts []DependencyConflict `json:"conflicts"`
+}
+
+// Hotspot is an instant where many jobs fire at once
+type Hotspot struct {
+	Time time.Time `json:"time"`
+	Jobs []string  `json:"jobs"`
+}
+
+// DependencyConflict is a job scheduled badly against one of its upstreams
+type DependencyConflict struct {
+	Job      string `json:"job"`
+	Upstream string `json:"upstream"`
+	Reason   string `json:"reason"`
+	// First is the first fire of Job with the problem, and Count the number
+	// of its fires that have it
+	First time.Time `json:"first,omitzero"`
+	Count int       `json:"count,omitempty"`
+}
+
+// AnalyzeSchedules previews every job's fires over the next horizon, as
+// PreviewRuns does, and reports the instants where threshold or more jobs
+// fire together along with the jobs scheduled badly against the upstreams
+// declared with WithUpstream: a job conflicts with an upstream that is not
+// registered, that fires at the same time, that has not finished by then on
+// its average run time, or whose next fire is closer than its last one, so
+// the job most likely consumes a stale result.
+func (ec *EnhancedCron) AnalyzeSchedules(horizon time.Duration, threshold int) (*ScheduleAnalysis, error) {
+	if horizon <= 0 {
+		return nil, fmt.Errorf("horizon must be positive, got %s", horizon)
+	}
+	if threshold < 2 {
+		return nil, fmt.Errorf("threshold must be at least 2, got %d", threshold)
+	}
+
+	ec.mu.RLock()
+	entries := make(map[string]*jobEntry, len(ec.jobs))
+	for name, entry := range ec.jobs {
+		entries[name] = entry
+	}
+	ec.mu.RUnlock()
+
+	now := ec.clock.Now().Round(0)
+	analysis := &ScheduleAnalysis{From: now, To: now.Add(horizon)}
+	fires := make(map[string][]time.Time, len(entries))
+	slots := make(map[time.Time][]string)
+	for name, entry := range entries {
+		fires[name] = entry.firesUntil(now, analysis.To)
+		for _, t := range fires[name] {
+			slots[t] = append(slots[t], name)
+		}
+	}
+
+	for t, jobs := range slots {
+		if len(jobs) >= threshold {
+			sort.Strings(jobs)
+			analysis.Hotspots = append(analysis.Hotspots, Hotspot{Time: t, Jobs: jobs})
+		}
+	}
+	sort.Slice(analysis.Hotspots, func(i, j int) bool {
+		return analysis.Hotspots[i].Time.Before(analysis.Hotspots[j].Time)
+	})
+
+	for name, entry := range entries {
+		for _, upstream := range entry.cfg.upstreams {
+			up, ok := entries[upstream]
+			if !ok {
+				analysis.Conflicts = append(analysis.Conflicts, DependencyConflict{
+					Job: name, Upstream: upstream, Reason: "upstream is not registered",
+				})
+				continue
+			}
+			analysis.Conflicts = append(analysis.Conflicts,
+				dependencyConflicts(name, upstream, fires[name], up.firesAround(now, analysis.To), up.averageDuration())...)
+		}
+	}
+	sort.Slice(analysis.Conflicts, func(i, j int) bool {
+		a, b := analysis.Conflicts[i], analysis.Conflicts[j]
+		if a.Job != b.Job {
+			return a.Job < b.Job
+		}
+		return a.Upstream < b.Upstream
+	})
+	return analysis, nil
+}
+
+// dependencyConflicts checks each fire of a job against the fires of an
+// upstream that runs for duration on average
+func dependencyConflicts(job, upstream string, fires, upFires []time.Time, duration time.Duration) []DependencyConflict {
+	var conflicts []DependencyConflict
+	found := make(map[string]int)
+	report := func(reason string, t time.Time) {
+		if i, ok := found[reason]; ok {
+			conflicts[i].Count++
+			return
+		}
+		found[reason] = len(conflicts)
+		conflicts = append(conflicts, DependencyConflict{Job: job, Upstream: upstream, Reason: reason, First: t, Count: 1})
+	}
+
+	for _, t := range fires {
+		// i is the upstream's first fire after t
+		i := sort.Search(len(upFires), func(i int) bool { return upFires[i].After(t) })
+		var prev, next time.Time
+		if i > 0 {
+			prev = upFires[i-1]
+		}
+		if i < len(upFires) {
+			next = upFires[i]
+		}
+		switch {
+		case prev.Equal(t):
+			report("fires at the same time as its upstream", t)
+		case !prev.IsZero() && duration > 0 && t.Sub(prev) < duration:
+			report("fires before its upstream usually finishes", t)
+		case !prev.IsZero() && !next.IsZero() && next.Sub(t) < t.Sub(prev):
+			report("fires shortly before its upstream", t)
+		}
+	}
+	return conflicts
+}
+
+// firesUntil returns the fires of the job after from up to to that its
+// windows and holiday calendar let run
+func (entry *jobEntry) firesUntil(from, to time.Time) []time.Time {
+	var fires []time.Time
+	t := from
+	for i := 0; i < maxPreviewScan; i++ {
+		if t = entry.next(t); t.IsZero() || t.After(to) {
+			break
+		}
+		if entry.skipReason(t) == "" {
+			fires = append(fires, t)
+		}
+	}
+	return fires
+}
+
+// firesAround returns the fires of an upstream job up to to, along with the
+// last one before from and the first one after to, so every fire of a
+// dependent job has upstream fires on both sides to compare with
+func (entry *jobEntry) firesAround(from, to time.Time) []time.Time {
+	fires := entry.firesUntil(from.Add(-to.Sub(from)), to)
+	t := to
+	for i := 0; i < maxPreviewScan; i++ {
+		if t = entry.next(t); t.IsZero() {
+			break
+		}
+		if entry.skipReason(t) == "" {
+			fires = append(fires, t)
+			break
+		}
+	}
+
+	// Keep a single fire before from
+	i := sort.Search(len(fires), func(i int) bool { return fires[i].After(from) })
+	if i > 1 {
+		fires = fires[i-1:]
+	}
+	return fires
+}
+
+// averageDuration averages the job's completed runs in its history
+func (entry *jobEntry) averageDuration() time.Duration {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	var total time.Duration
+	var n int
+	for _, m := range entry.history {
+		if m.Status == StatusCompleted && !m.EndTime.IsZero() {
+			total += m.EndTime.Sub(m.StartTime)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return total / time.Duration(n)
+}
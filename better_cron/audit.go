@@ -0,0 +1,192 @@
+package better_cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Audit actions besides the run events, which are recorded under their
+// EventType names
+const (
+	AuditRegistered = "registered"
+	AuditUpdated    = "updated"
+	AuditRemoved    = "removed"
+	AuditTriggered  = "triggered"
+	AuditFired      = "fired"
+)
+
+// ActorAPI is the audit actor of jobs changed through the Go API without
+// WithAuditActor
+const ActorAPI = "api"
+
+// AuditRecord is one entry of the audit log
+type AuditRecord struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	Job    string    `json:"job"`
+	RunID  string    `json:"run_id,omitempty"`
+	// Actor is who or what initiated the action: ActorAPI, "config:" and
+	// the file path, or the trigger of a run
+	Actor  string `json:"actor"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// AuditLog receives every audit record. Implementations must only ever
+// append, and are called synchronously like an EventSink.
+type AuditLog interface {
+	Append(record AuditRecord) error
+}
+
+// WithAuditLog records job registrations, updates and removals, every fire
+// and manual trigger, and every run event to log
+func WithAuditLog(log AuditLog) Option {
+	return func(ec *EnhancedCron) {
+		ec.audit = log
+	}
+}
+
+// WithAuditActor names who registered or updated the job in the audit log
+func WithAuditActor(actor string) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.actor = actor
+	}
+}
+
+// AuditFile is an AuditLog appending one JSON object per line to a file
+type AuditFile struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenAuditFile opens path for appending, creating it if needed
+func OpenAuditFile(path string) (*AuditFile, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	return &AuditFile{f: f}, nil
+}
+
+// Append writes the record as a single line
+func (a *AuditFile) Append(record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.f.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file
+func (a *AuditFile) Close() error {
+	return a.f.Close()
+}
+
+// auditPrefix is the store key prefix of audit records
+const auditPrefix = "audit/"
+
+// StoreAuditLog is an AuditLog keeping each record under its own key of a
+// Store, ordered by time
+type StoreAuditLog struct {
+	store Store
+	seq   atomic.Uint64
+}
+
+// NewStoreAuditLog creates an audit log writing to store
+func NewStoreAuditLog(store Store) *StoreAuditLog {
+	return &StoreAuditLog{store: store}
+}
+
+// Append stores the record under a new key
+func (a *StoreAuditLog) Append(record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s%020d-%010d", auditPrefix, record.Time.UnixNano(), a.seq.Add(1))
+	return a.store.Put(key, data)
+}
+
+// List returns the stored records, oldest first
+func (a *StoreAuditLog) List() ([]AuditRecord, error) {
+	keys, err := a.store.List(auditPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]AuditRecord, 0, len(keys))
+	for _, key := range keys {
+		data, ok, err := a.store.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		var record AuditRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("decode audit record %s: %w", key, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// recordTrigger records a fire, or a run started outside the schedule
+func (ec *EnhancedCron) recordTrigger(entry *jobEntry, f firing) {
+	action := AuditTriggered
+	if f.trigger == TriggerSchedule {
+		action = AuditFired
+	}
+	ec.record(AuditRecord{Time: f.scheduled, Action: action, Job: entry.name, Actor: f.trigger})
+}
+
+// record appends to the audit log, if one is configured
+func (ec *EnhancedCron) record(record AuditRecord) {
+	if ec.audit == nil {
+		return
+	}
+	if record.Time.IsZero() {
+		record.Time = ec.clock.Now()
+	}
+	if err := ec.audit.Append(record); err != nil {
+		ec.logger.Error("audit: %s of job %s: %v", record.Action, record.Job, err)
+	}
+}
+
+// auditEvent records a run event; progress reports are left out
+func (ec *EnhancedCron) auditEvent(event JobEvent) {
+	if ec.audit == nil || event.Type == EventJobProgress {
+		return
+	}
+
+	record := AuditRecord{
+		Time:   event.Time,
+		Action: event.Type.String(),
+		Job:    event.Job,
+		RunID:  event.Metadata.RunID,
+		Actor:  event.Metadata.Trigger,
+		Detail: event.Reason,
+	}
+	if record.Actor == "" {
+		record.Actor = TriggerSchedule
+	}
+	if event.Metadata.Error != nil {
+		record.Detail = event.Metadata.Error.Error()
+	}
+	ec.record(record)
+}
+
+// auditActor returns who changed a job with these options
+func (cfg *jobConfig) auditActor() string {
+	if cfg.actor == "" {
+		return ActorAPI
+	}
+	return cfg.actor
+}
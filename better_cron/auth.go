@@ -0,0 +1,159 @@
+package better_cron
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Role is a permission level of the admin API; each role includes the ones
+// below it
+type Role int
+
+const (
+	// RoleViewer may list jobs, runs, history and log levels
+	RoleViewer Role = iota + 1
+	// RoleOperator may also trigger jobs, cancel runs and change log levels
+	RoleOperator
+	// RoleAdmin may also remove jobs
+	RoleAdmin
+)
+
+// Convert Role to string
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleOperator:
+		return "operator"
+	case RoleAdmin:
+		return "admin"
+	}
+	return "none"
+}
+
+// ParseRole parses the name of a role
+func ParseRole(name string) (Role, error) {
+	for _, role := range []Role{RoleViewer, RoleOperator, RoleAdmin} {
+		if strings.EqualFold(name, role.String()) {
+			return role, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown role %q", name)
+}
+
+// Principal is an authenticated caller of the admin API
+type Principal struct {
+	Name string
+	Role Role
+}
+
+// ErrUnauthenticated is returned by an Authenticator for requests without
+// valid credentials
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authenticator identifies the caller of an admin API request. OIDC and
+// other schemes plug in through AuthenticatorFunc, e.g. verifying the
+// bearer token with an OIDC library and mapping its claims to a role.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// AuthenticatorFunc adapts a plain function to the Authenticator interface
+type AuthenticatorFunc func(r *http.Request) (Principal, error)
+
+// Authenticate calls f(r)
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (Principal, error) {
+	return f(r)
+}
+
+// WithAuthenticator requires every admin API request to be authenticated.
+// Without one, every caller is an anonymous admin, so the handler must
+// then be protected by the server it is mounted in.
+func WithAuthenticator(auth Authenticator) AdminOption {
+	return func(cfg *adminConfig) {
+		cfg.auth = auth
+	}
+}
+
+// TokenAuthenticator authenticates "Authorization: Bearer <token>" headers
+// against a fixed set of API tokens
+type TokenAuthenticator struct {
+	tokens map[string]Principal
+}
+
+// NewTokenAuthenticator creates an authenticator accepting the given
+// tokens, each mapped to the principal it identifies
+func NewTokenAuthenticator(tokens map[string]Principal) *TokenAuthenticator {
+	return &TokenAuthenticator{tokens: tokens}
+}
+
+// Authenticate looks up the request's bearer token
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		return Principal{}, ErrUnauthenticated
+	}
+	// Compare against every token so timing does not reveal a prefix
+	var found Principal
+	for known, principal := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(known)) == 1 {
+			found = principal
+		}
+	}
+	if found.Role == 0 {
+		return Principal{}, ErrUnauthenticated
+	}
+	return found, nil
+}
+
+// BearerToken returns the token of the request's Authorization header
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// anonymous is the caller when no Authenticator is configured
+var anonymous = Principal{Name: "anonymous", Role: RoleAdmin}
+
+// PrincipalFromContext returns the caller of an admin API request
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey).(Principal)
+	return p, ok
+}
+
+// require wraps an admin handler so it only serves callers with role
+func (a *admin) require(role Role, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := anonymous
+		if a.cfg.auth != nil {
+			var err error
+			if principal, err = a.cfg.auth.Authenticate(r); err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, err)
+				return
+			}
+		}
+		if (a.cfg.readOnly || a.ec.readOnly) && r.Method != http.MethodGet {
+			writeError(w, http.StatusForbidden, ErrReadOnly)
+			return
+		}
+		if principal.Role < role {
+			writeError(w, http.StatusForbidden, fmt.Errorf("%s role required, %s is %s", role, principal.Name, principal.Role))
+			return
+		}
+		handler(w, r.WithContext(context.WithValue(r.Context(), principalKey, principal)))
+	}
+}
+
+// caller returns the audit actor of an admin API request
+func caller(r *http.Request) string {
+	principal, _ := PrincipalFromContext(r.Context())
+	return "admin:" + principal.Name
+}
@@ -0,0 +1,82 @@
+package better_cron
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxBackoffLevel bounds how many times a failing job's interval doubles
+const maxBackoffLevel = 16
+
+// WithScheduleBackoff stretches the job's schedule while it keeps failing:
+// after each failed run in a row the interval to the next fire doubles, by
+// skipping fires of the spec, as long as the next fire stays within max.
+// The first successful run restores the configured spec. It suits polling
+// jobs that hit a dependency that is down.
+func WithScheduleBackoff(max time.Duration) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.backoffMax = max
+	}
+}
+
+// backoffSchedule skips fires of a schedule by the job's backoff level
+type backoffSchedule struct {
+	cron.Schedule
+	max   time.Duration
+	level *atomic.Int32
+}
+
+// Next returns the fire 2^level fires ahead, or the last one within max
+func (s backoffSchedule) Next(t time.Time) time.Time {
+	next := s.Schedule.Next(t)
+	level := s.level.Load()
+	if level == 0 || next.IsZero() {
+		return next
+	}
+	for i := 1; i < 1<<level; i++ {
+		later := s.Schedule.Next(next)
+		if later.IsZero() || later.Sub(t) > s.max {
+			break
+		}
+		next = later
+	}
+	return next
+}
+
+// recordBackoff moves the job's backoff level with the failure streak and
+// reschedules its next fire when the level changed
+func (ec *EnhancedCron) recordBackoff(entry *jobEntry, streak int) {
+	if entry.cfg.backoffMax <= 0 {
+		return
+	}
+	level := int32(min(streak, maxBackoffLevel))
+	if entry.backoffLevel.Swap(level) == level {
+		return
+	}
+
+	next := ec.rescheduleJob(entry)
+	if next.IsZero() {
+		return
+	}
+	if level > 0 {
+		ec.logger.Info("job %s failed %d times in a row, backing off until %s", entry.name, streak, next.Format(time.RFC3339))
+	} else {
+		ec.logger.Info("job %s recovered, back on its schedule at %s", entry.name, next.Format(time.RFC3339))
+	}
+}
+
+// rescheduleJob recomputes the next fire of the job's current registration
+// after state its schedule reads changed, returning the zero time if the
+// job is gone or not scheduled
+func (ec *EnhancedCron) rescheduleJob(entry *jobEntry) time.Time {
+	// The run may have outlived an update of the job, which shares its state
+	ec.mu.RLock()
+	current, ok := ec.jobs[entry.name]
+	ec.mu.RUnlock()
+	if !ok || current.jobState != entry.jobState {
+		return time.Time{}
+	}
+	return ec.sched.reschedule(current.id)
+}
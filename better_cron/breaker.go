@@ -0,0 +1,171 @@
+package better_cron
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState represents the state of a job's circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+// Convert BreakerState to string
+func (s BreakerState) String() string {
+	return [...]string{"closed", "open", "half-open"}[s]
+}
+
+// WithCircuitBreaker opens the job's breaker after threshold consecutive
+// failures. While open, fires are skipped; after cooldown a single probe run
+// is let through and its outcome closes or reopens the breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.breakerThreshold = threshold
+		cfg.breakerCooldown = cooldown
+	}
+}
+
+// circuitBreaker tracks consecutive failures of a single job
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a fire may run, whether it is the probe of a
+// half-open breaker and whether it moved the breaker into half-open
+func (b *circuitBreaker) allow(now time.Time) (allowed, probe, halfOpened bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false, false, false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true, true, true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false, false, false
+		}
+		b.probing = true
+		return true, true, false
+	default:
+		return true, false, false
+	}
+}
+
+// release gives back the probe of a fire that did not run, so the next
+// fire probes instead
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record feeds the outcome of a run into the breaker and returns the new
+// state and whether it changed
+func (b *circuitBreaker) record(status JobStatus, now time.Time) (BreakerState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	previous := b.state
+
+	switch status {
+	case StatusCompleted:
+		b.failures = 0
+		b.state = BreakerClosed
+	case StatusFailed:
+		b.failures++
+		if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+			b.state = BreakerOpen
+			b.openedAt = now
+		}
+	}
+
+	return b.state, b.state != previous
+}
+
+func (b *circuitBreaker) current() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// allowByBreaker checks the job's breaker before a fire, emitting events
+// for skipped fires and half-open transitions. The probe result is set if
+// the fire holds the probe of a half-open breaker, which releaseProbe gives
+// back if the fire does not run.
+func (ec *EnhancedCron) allowByBreaker(entry *jobEntry) (probe, ok bool) {
+	if entry.breaker == nil {
+		return false, true
+	}
+
+	allowed, probe, halfOpened := entry.breaker.allow(ec.clock.Now())
+	if halfOpened {
+		ec.logger.Info("circuit breaker for job %s is half-open, running probe", entry.name)
+		ec.emit(EventBreakerHalfOpen, entry, &JobMetadata{ID: entry.id, Name: entry.name})
+	}
+	if !allowed {
+		ec.emitSkipped(entry, "circuit breaker open")
+	}
+	return probe, allowed
+}
+
+// releaseProbe gives back the probe of a fire that was skipped after
+// allowByBreaker let it through
+func (ec *EnhancedCron) releaseProbe(entry *jobEntry, probe bool) {
+	if probe {
+		entry.breaker.release()
+	}
+}
+
+// recordBreaker updates the job's breaker with the outcome of a finished run
+func (ec *EnhancedCron) recordBreaker(entry *jobEntry, metadata *JobMetadata) {
+	if entry.breaker == nil {
+		return
+	}
+
+	state, changed := entry.breaker.record(metadata.Status, ec.clock.Now())
+	if !changed {
+		return
+	}
+
+	switch state {
+	case BreakerOpen:
+		ec.logger.Error("circuit breaker for job %s opened after failure: %v", entry.name, metadata.Error)
+		ec.emit(EventBreakerOpened, entry, metadata)
+	case BreakerClosed:
+		ec.logger.Info("circuit breaker for job %s closed", entry.name)
+		ec.emit(EventBreakerClosed, entry, metadata)
+	}
+}
+
+// GetBreakerState returns the circuit breaker state of a job by name. The
+// second result is false if the job does not exist or has no breaker.
+func (ec *EnhancedCron) GetBreakerState(name string) (BreakerState, bool) {
+	ec.mu.RLock()
+	entry, ok := ec.jobs[name]
+	ec.mu.RUnlock()
+
+	if !ok || entry.breaker == nil {
+		return BreakerClosed, false
+	}
+	return entry.breaker.current(), true
+}
@@ -0,0 +1,66 @@
+package better_cron
+
+import "time"
+
+// WithExecutionBudget caps the job's total run time to limit per window,
+// e.g. WithExecutionBudget(30*time.Minute, 24*time.Hour). Once runs in the
+// current window have used the budget, further fires are skipped until the
+// next window. Windows of a day or less start at midnight in the job's
+// timezone; a run is charged to the window it ends in.
+func WithExecutionBudget(limit, window time.Duration) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.budget = limit
+		cfg.budgetWindow = window
+	}
+}
+
+// budgetWindowStart returns the start of the budget window containing t
+func (entry *jobEntry) budgetWindowStart(t time.Time) time.Time {
+	window := entry.cfg.budgetWindow
+	if window > 24*time.Hour {
+		return t.Truncate(window)
+	}
+	t = t.In(entry.location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.Add(t.Sub(midnight) / window * window)
+}
+
+// rollBudget resets the used budget when t is in a later window; entry.mu
+// must be held
+func (entry *jobEntry) rollBudget(t time.Time) {
+	if start := entry.budgetWindowStart(t); !start.Equal(entry.budgetStart) {
+		entry.budgetStart = start
+		entry.budgetUsed = 0
+	}
+}
+
+// checkBudget reports false, emitting a skip, when the job has used its
+// execution budget for the current window
+func (ec *EnhancedCron) checkBudget(entry *jobEntry) bool {
+	if entry.cfg.budget <= 0 || entry.cfg.budgetWindow <= 0 {
+		return true
+	}
+
+	entry.mu.Lock()
+	entry.rollBudget(ec.clock.Now())
+	exhausted := entry.budgetUsed >= entry.cfg.budget
+	entry.mu.Unlock()
+
+	if exhausted {
+		ec.emitSkipped(entry, "execution budget exhausted")
+		return false
+	}
+	return true
+}
+
+// chargeBudget adds a finished run's duration to the job's used budget
+func (ec *EnhancedCron) chargeBudget(entry *jobEntry, metadata *JobMetadata) {
+	if entry.cfg.budget <= 0 || entry.cfg.budgetWindow <= 0 {
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	entry.rollBudget(metadata.EndTime)
+	entry.budgetUsed += metadata.EndTime.Sub(metadata.StartTime)
+}
@@ -0,0 +1,227 @@
+package better_cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// specUnit is the unit a SpecBuilder repeats in
+type specUnit int
+
+const (
+	unitNone specUnit = iota
+	unitSeconds
+	unitMinutes
+	unitHours
+	unitDays
+)
+
+// SpecBuilder builds a validated cron spec step by step, e.g.
+//
+//	spec, err := better_cron.Every(5).Minutes().OnWeekdays().At("09:00").Spec()
+//
+// yields "0 */5 9-23 * * 1-5". For minutes and hours At sets when the
+// repetition starts each day; for days it is the time of day to fire at.
+// Mistakes are reported by Spec rather than by each step.
+type SpecBuilder struct {
+	every int
+	unit  specUnit
+	days  []time.Weekday
+	at    *[3]int
+	tz    string
+	err   error
+}
+
+// Every starts a spec that repeats every n units
+func Every(n int) *SpecBuilder {
+	b := &SpecBuilder{every: n}
+	if n < 1 {
+		b.fail("every must be at least 1, got %d", n)
+	}
+	return b
+}
+
+// Seconds repeats the spec every n seconds
+func (b *SpecBuilder) Seconds() *SpecBuilder { return b.setUnit(unitSeconds, 59) }
+
+// Minutes repeats the spec every n minutes
+func (b *SpecBuilder) Minutes() *SpecBuilder { return b.setUnit(unitMinutes, 59) }
+
+// Hours repeats the spec every n hours
+func (b *SpecBuilder) Hours() *SpecBuilder { return b.setUnit(unitHours, 23) }
+
+// Days repeats the spec every n days of the month
+func (b *SpecBuilder) Days() *SpecBuilder { return b.setUnit(unitDays, 31) }
+
+// On restricts the spec to the given days of the week
+func (b *SpecBuilder) On(days ...time.Weekday) *SpecBuilder {
+	b.days = append(b.days, days...)
+	return b
+}
+
+// OnWeekdays restricts the spec to Monday through Friday
+func (b *SpecBuilder) OnWeekdays() *SpecBuilder {
+	return b.On(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+}
+
+// OnWeekends restricts the spec to Saturday and Sunday
+func (b *SpecBuilder) OnWeekends() *SpecBuilder {
+	return b.On(time.Saturday, time.Sunday)
+}
+
+// At sets the time of day as "HH:MM" or "HH:MM:SS"
+func (b *SpecBuilder) At(clock string) *SpecBuilder {
+	parts := strings.Split(clock, ":")
+	var at [3]int
+	limits := [3]int{23, 59, 59}
+	if len(parts) < 2 || len(parts) > 3 {
+		return b.fail("invalid time %q, expected HH:MM or HH:MM:SS", clock)
+	}
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil || v < 0 || v > limits[i] {
+			return b.fail("invalid time %q, expected HH:MM or HH:MM:SS", clock)
+		}
+		at[i] = v
+	}
+	b.at = &at
+	return b
+}
+
+// In evaluates the spec in the named time zone, e.g. "Europe/Berlin"
+func (b *SpecBuilder) In(zone string) *SpecBuilder {
+	if _, err := time.LoadLocation(zone); err != nil {
+		return b.fail("invalid time zone %q: %v", zone, err)
+	}
+	b.tz = zone
+	return b
+}
+
+// Spec returns the built spec, validated with ValidateSpec, or the first
+// mistake made while building it
+func (b *SpecBuilder) Spec() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if b.unit == unitNone {
+		return "", fmt.Errorf("spec builder: no unit given, e.g. Every(%d).Minutes()", b.every)
+	}
+
+	sec, min, hour, dom, dow := "0", "0", "0", "*", "*"
+	var at [3]int
+	if b.at != nil {
+		at = *b.at
+	}
+	switch b.unit {
+	case unitSeconds:
+		if b.at != nil {
+			return "", fmt.Errorf("spec builder: At cannot be combined with Seconds")
+		}
+		sec = step(0, 59, b.every)
+		min, hour = "*", "*"
+	case unitMinutes:
+		// The minute of At offsets the steps, which repeat every hour
+		if at[1] >= b.every {
+			return "", fmt.Errorf("spec builder: every %d minutes cannot start at minute %d of the hour", b.every, at[1])
+		}
+		sec = strconv.Itoa(at[2])
+		min = step(at[1], 59, b.every)
+		hour = step(at[0], 23, 1)
+	case unitHours:
+		sec, min = strconv.Itoa(at[2]), strconv.Itoa(at[1])
+		hour = step(at[0], 23, b.every)
+	case unitDays:
+		sec, min, hour = strconv.Itoa(at[2]), strconv.Itoa(at[1]), strconv.Itoa(at[0])
+		dom = step(0, 31, b.every)
+		if b.every > 1 && len(b.days) > 0 {
+			return "", fmt.Errorf("spec builder: every %d days cannot be combined with days of the week", b.every)
+		}
+	}
+	if len(b.days) > 0 {
+		dow = weekdayList(b.days)
+	}
+
+	spec := strings.Join([]string{sec, min, hour, dom, "*", dow}, " ")
+	if b.tz != "" {
+		spec = "CRON_TZ=" + b.tz + " " + spec
+	}
+	if _, err := ValidateSpec(spec); err != nil {
+		return "", err
+	}
+	return spec, nil
+}
+
+// MustSpec is like Spec but panics on a mistake, for specs fixed in code
+func (b *SpecBuilder) MustSpec() string {
+	spec, err := b.Spec()
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+func (b *SpecBuilder) setUnit(unit specUnit, max int) *SpecBuilder {
+	if b.unit != unitNone {
+		return b.fail("unit given twice")
+	}
+	b.unit = unit
+	if b.every > max {
+		b.fail("every %d is out of range, at most %d", b.every, max)
+	}
+	return b
+}
+
+// fail keeps the first mistake made while building
+func (b *SpecBuilder) fail(format string, args ...interface{}) *SpecBuilder {
+	if b.err == nil {
+		b.err = fmt.Errorf("spec builder: "+format, args...)
+	}
+	return b
+}
+
+// step formats a field repeating every n from start up to max
+func step(start, max, n int) string {
+	switch {
+	case start == 0 && n == 1:
+		return "*"
+	case start == 0:
+		return "*/" + strconv.Itoa(n)
+	case n == 1:
+		return fmt.Sprintf("%d-%d", start, max)
+	}
+	return fmt.Sprintf("%d/%d", start, n)
+}
+
+// weekdayList formats days as a day-of-week field, collapsing runs of
+// consecutive days into ranges
+func weekdayList(days []time.Weekday) string {
+	var set [7]bool
+	for _, d := range days {
+		set[d] = true
+	}
+	var sorted []int
+	for d, ok := range set {
+		if ok {
+			sorted = append(sorted, d)
+		}
+	}
+
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			parts = append(parts, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				parts = append(parts, strconv.Itoa(sorted[k]))
+			}
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
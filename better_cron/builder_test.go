@@ -0,0 +1,66 @@
+package better_cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpecBuilder(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *SpecBuilder
+		want string
+	}{
+		{"every 10 seconds", Every(10).Seconds(), "*/10 * * * * *"},
+		{"every 5 minutes on weekdays from 09:00", Every(5).Minutes().OnWeekdays().At("09:00"), "0 */5 9-23 * * 1-5"},
+		{"every 15 minutes from minute 5", Every(15).Minutes().At("00:05"), "0 5/15 * * * *"},
+		{"every 2 hours", Every(2).Hours(), "0 0 */2 * * *"},
+		{"every 3 hours from 01:30", Every(3).Hours().At("01:30"), "0 30 1/3 * * *"},
+		{"daily", Every(1).Days().At("06:30"), "0 30 6 * * *"},
+		{"every 2 days", Every(2).Days().At("12:00:15"), "15 0 12 */2 * *"},
+		{"weekends", Every(1).Days().OnWeekends(), "0 0 0 * * 0,6"},
+		{"in a zone", Every(1).Days().On(time.Wednesday, time.Monday).At("18:00").In("Europe/Berlin"), "CRON_TZ=Europe/Berlin 0 0 18 * * 1,3"},
+	}
+	for _, tt := range tests {
+		got, err := tt.b.Spec()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSpecBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *SpecBuilder
+	}{
+		{"zero interval", Every(0).Minutes()},
+		{"no unit", Every(5)},
+		{"unit given twice", Every(1).Minutes().Hours()},
+		{"interval out of range", Every(61).Minutes()},
+		{"start past the interval", Every(5).Minutes().At("09:07")},
+		{"seconds at a time", Every(10).Seconds().At("09:00")},
+		{"invalid time", Every(1).Days().At("25:00")},
+		{"malformed time", Every(1).Days().At("9")},
+		{"unknown zone", Every(1).Days().In("Mars/Olympus_Mons")},
+		{"days of the week every 2 days", Every(2).Days().On(time.Monday)},
+	}
+	for _, tt := range tests {
+		if spec, err := tt.b.Spec(); err == nil {
+			t.Errorf("%s: got %q, want an error", tt.name, spec)
+		}
+	}
+}
+
+func TestSpecBuilderMustSpecPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustSpec did not panic on a mistake")
+		}
+	}()
+	Every(0).Minutes().MustSpec()
+}
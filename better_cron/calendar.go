@@ -0,0 +1,180 @@
+package better_cron
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Calendar decides which days jobs marked WithCalendar must not run on
+type Calendar interface {
+	// IsHoliday reports whether the day t falls on, in t's location, is a holiday
+	IsHoliday(t time.Time) bool
+}
+
+// HolidayCalendar is a Calendar backed by a fixed set of dates
+type HolidayCalendar struct {
+	// Weekends treats every Saturday and Sunday as a holiday too
+	Weekends bool
+
+	mu    sync.RWMutex
+	dates map[string]bool
+}
+
+// NewHolidayCalendar creates a calendar with the given holidays
+func NewHolidayCalendar(dates ...time.Time) *HolidayCalendar {
+	cal := &HolidayCalendar{dates: make(map[string]bool)}
+	cal.Add(dates...)
+	return cal
+}
+
+// LoadHolidayCalendar reads a file with one YYYY-MM-DD date per line. Blank
+// lines and text after a # are ignored, so dates can be annotated.
+func LoadHolidayCalendar(path string) (*HolidayCalendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cal := NewHolidayCalendar()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid date %q", path, line, text)
+		}
+		cal.Add(date)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cal, nil
+}
+
+// Add marks the days of the given times as holidays
+func (c *HolidayCalendar) Add(dates ...time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range dates {
+		c.dates[d.Format(time.DateOnly)] = true
+	}
+}
+
+// IsHoliday reports whether t falls on a listed date or, with Weekends set,
+// on a weekend
+func (c *HolidayCalendar) IsHoliday(t time.Time) bool {
+	if c.Weekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return true
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dates[t.Format(time.DateOnly)]
+}
+
+// HolidayPolicy controls what happens to a fire that lands on a holiday
+type HolidayPolicy int
+
+const (
+	// HolidaySkip drops the fire
+	HolidaySkip HolidayPolicy = iota
+	// HolidayDefer runs the fire at the same time on the next business day,
+	// unless the job fires then anyway. Further fires during the same
+	// stretch of holidays are dropped.
+	HolidayDefer
+)
+
+// WithCalendar skips fires that land on a holiday of cal, evaluated in the
+// job's time zone
+func WithCalendar(cal Calendar) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.calendar = cal
+	}
+}
+
+// WithHolidayPolicy sets how holiday fires of a WithCalendar job are handled
+func WithHolidayPolicy(policy HolidayPolicy) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.holidayPolicy = policy
+	}
+}
+
+// maxDeferDays bounds the search for the next business day
+const maxDeferDays = 366
+
+// checkCalendar reports whether the fire may proceed. Holiday fires are
+// skipped or, under HolidayDefer, rescheduled for the next business day.
+func (ec *EnhancedCron) checkCalendar(job cron.Job, entry *jobEntry, f firing) bool {
+	cal := entry.cfg.calendar
+	if cal == nil {
+		return true
+	}
+	t := f.scheduled.In(entry.location)
+	if !cal.IsHoliday(t) {
+		return true
+	}
+
+	if entry.cfg.holidayPolicy != HolidayDefer {
+		ec.emitSkipped(entry, "holiday")
+		return false
+	}
+
+	next := t
+	for i := 0; i < maxDeferDays; i++ {
+		next = next.AddDate(0, 0, 1)
+		if !cal.IsHoliday(next) {
+			break
+		}
+	}
+	if cal.IsHoliday(next) {
+		ec.emitSkipped(entry, "holiday, no business day found")
+		return false
+	}
+
+	// A job that fires on the business day anyway needs no extra run
+	if entry.next(next.Add(-time.Nanosecond)).Equal(next) {
+		ec.emitSkipped(entry, "holiday, the job already fires at "+next.Format(time.RFC3339))
+		return false
+	}
+
+	entry.mu.Lock()
+	pending := entry.deferred
+	entry.deferred = true
+	entry.mu.Unlock()
+	if pending {
+		ec.emitSkipped(entry, "holiday, a run is already deferred")
+		return false
+	}
+
+	ec.emitSkipped(entry, "holiday, deferred to "+next.Format(time.RFC3339))
+	timer := ec.clock.NewTimer(next.Sub(ec.clock.Now()))
+	go func() {
+		defer timer.Stop()
+		due := false
+		select {
+		case <-ec.stoppingContext().Done():
+		case <-timer.C():
+			due = true
+		}
+		entry.mu.Lock()
+		entry.deferred = false
+		entry.mu.Unlock()
+		if due {
+			// Fire it as a trigger so a pause still holds it back
+			deferred := f
+			deferred.scheduled = next
+			ec.triggerFiring(job, entry, deferred)
+		}
+	}()
+	return false
+}
@@ -0,0 +1,46 @@
+package better_cron
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// canaryConfig is the cadence and warning threshold of the canary
+type canaryConfig struct {
+	interval time.Duration
+	warn     time.Duration
+}
+
+// WithCanary fires an internal canary every interval and measures how late
+// each fire comes relative to when it was due. The delay is published as
+// the "scheduler.drift_seconds" metric and returned by SchedulingDrift, and
+// a delay beyond warnAfter is logged as a sign the process is too loaded
+// to fire jobs on time. The canary is not a job: it runs no pipeline, emits
+// no events and is not listed. Intervals below a second are rounded up.
+func WithCanary(interval, warnAfter time.Duration) Option {
+	return func(ec *EnhancedCron) {
+		ec.canary = canaryConfig{interval: interval, warn: warnAfter}
+	}
+}
+
+// scheduleCanary adds the canary to the scheduler
+func (ec *EnhancedCron) scheduleCanary() {
+	var id cron.EntryID
+	id = ec.sched.Schedule(cron.Every(ec.canary.interval), cron.FuncJob(func() {
+		// The run loop sets Prev to the due time before releasing its lock
+		due := ec.sched.Entry(id).Prev
+		drift := ec.clock.Now().Sub(due)
+		ec.lastDrift.Store(int64(drift))
+		ec.metrics.Gauge("scheduler.drift_seconds", drift.Seconds(), nil)
+		if ec.canary.warn > 0 && drift > ec.canary.warn {
+			ec.logger.Error("canary fired %v late, the scheduler may be overloaded", drift)
+		}
+	}))
+}
+
+// SchedulingDrift returns how late the canary's last fire came, or 0 when
+// WithCanary is not set or the canary has not fired yet
+func (ec *EnhancedCron) SchedulingDrift() time.Duration {
+	return time.Duration(ec.lastDrift.Load())
+}
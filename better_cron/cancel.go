@@ -0,0 +1,50 @@
+package better_cron
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRunCancelled is the error recorded on runs stopped by CancelRun or
+// CancelJob
+var ErrRunCancelled = errors.New("run cancelled")
+
+// errGracePeriodExpired is recorded on runs cut off by their grace period
+var errGracePeriodExpired = errors.New("shutdown grace period expired")
+
+// CancelRun cancels the context of a run in flight. The run is recorded as
+// cancelled once it returns; command jobs have their process group killed.
+func (ec *EnhancedCron) CancelRun(runID string) error {
+	if err := ec.writable(); err != nil {
+		return err
+	}
+	value, ok := ec.activeJobs.Load(runID)
+	if !ok {
+		return fmt.Errorf("run %q not found", runID)
+	}
+	run := value.(activeJob)
+	ec.logger.Info("cancelling run %s of job %s", runID, run.metadata.Name)
+	run.cancel(ErrRunCancelled)
+	return nil
+}
+
+// CancelJob cancels every run of the job that is in flight
+func (ec *EnhancedCron) CancelJob(name string) error {
+	if err := ec.writable(); err != nil {
+		return err
+	}
+	var cancelled int
+	ec.activeJobs.Range(func(key, value interface{}) bool {
+		run := value.(activeJob)
+		if run.metadata.Name == name {
+			ec.logger.Info("cancelling run %s of job %s", run.metadata.RunID, name)
+			run.cancel(ErrRunCancelled)
+			cancelled++
+		}
+		return true
+	})
+	if cancelled == 0 {
+		return fmt.Errorf("job %q has no runs in flight", name)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package better_cron
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// joinCgroup creates a cgroup for one run under limits.Cgroup and starts
+// cmd directly inside it. The returned func removes the group once the
+// process has exited.
+func joinCgroup(cmd *exec.Cmd, limits *ResourceLimits) (func(), error) {
+	if limits == nil || limits.Cgroup == "" {
+		return func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp(limits.Cgroup, "run-")
+	if err != nil {
+		return nil, fmt.Errorf("create cgroup: %w", err)
+	}
+	remove := func() { os.Remove(dir) }
+
+	settings := map[string]string{}
+	if limits.MaxMemory > 0 {
+		settings["memory.max"] = strconv.FormatInt(limits.MaxMemory, 10)
+	}
+	if limits.CPUWeight > 0 {
+		settings["cpu.weight"] = strconv.Itoa(limits.CPUWeight)
+	}
+	for name, value := range settings {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644); err != nil {
+			remove()
+			return nil, fmt.Errorf("set %s: %w", name, err)
+		}
+	}
+
+	group, err := os.Open(dir)
+	if err != nil {
+		remove()
+		return nil, fmt.Errorf("open cgroup: %w", err)
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(group.Fd())
+	return func() {
+		group.Close()
+		remove()
+	}, nil
+}
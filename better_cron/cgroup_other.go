@@ -0,0 +1,17 @@
+//go:build !linux
+
+package better_cron
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// joinCgroup fails where cgroups are not available
+func joinCgroup(cmd *exec.Cmd, limits *ResourceLimits) (func(), error) {
+	if limits == nil || limits.Cgroup == "" {
+		return func() {}, nil
+	}
+	return nil, fmt.Errorf("cgroups are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,166 @@
+package better_cron
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the scheduler's source of time. Tests can swap in a FakeClock
+// with WithClock to fire jobs deterministically instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single-shot timer created by a Clock
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// WithClock sets the clock used for schedules, fire times, run timestamps,
+// jitter, backoff, rate limiting and heartbeat deadlines. Fixed run
+// timeouts and the shutdown timeout always use wall-clock time.
+func WithClock(clock Clock) Option {
+	return func(ec *EnhancedCron) {
+		ec.clock = clock
+	}
+}
+
+// realClock is the wall clock
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.t.C }
+func (t realTimer) Stop() bool          { return t.t.Stop() }
+
+// FakeClock is a Clock that only moves when told to
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock creates a fake clock reading now
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a timer that fires once the clock is advanced by d
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance moves the clock forward by d, firing every timer that falls due
+// in deadline order
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.setLocked(c.now.Add(d))
+	c.mu.Unlock()
+}
+
+// Set moves the clock to t, firing every timer that falls due
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.setLocked(t)
+	c.mu.Unlock()
+}
+
+func (c *FakeClock) setLocked(t time.Time) {
+	c.now = t
+	sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].deadline.Before(c.timers[j].deadline) })
+
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(t) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.c <- t
+	}
+	c.timers = pending
+	c.cond.Broadcast()
+}
+
+// BlockUntil waits until at least n timers are pending, e.g. until a started
+// scheduler is waiting for its next fire, so Advance cannot race it
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// Pending returns the number of timers waiting to fire
+func (c *FakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// NextDeadline returns when the earliest pending timer fires
+func (c *FakeClock) NextDeadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var next time.Time
+	for _, t := range c.timers {
+		if next.IsZero() || t.deadline.Before(next) {
+			next = t.deadline
+		}
+	}
+	return next, !next.IsZero()
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+// Stop cancels the timer, reporting whether it was still pending
+func (t *fakeTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, timer := range c.timers {
+		if timer == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package better_cron
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeClockAdvance(t *testing.T) {
+	c := NewFakeClock(epoch)
+	if got := c.Now(); !got.Equal(epoch) {
+		t.Fatalf("Now = %v, want %v", got, epoch)
+	}
+
+	c.Advance(90 * time.Second)
+	if got, want := c.Now(), epoch.Add(90*time.Second); !got.Equal(want) {
+		t.Errorf("Now after Advance = %v, want %v", got, want)
+	}
+	c.Set(epoch.Add(time.Hour))
+	if got, want := c.Now(), epoch.Add(time.Hour); !got.Equal(want) {
+		t.Errorf("Now after Set = %v, want %v", got, want)
+	}
+}
+
+func TestFakeClockTimers(t *testing.T) {
+	c := NewFakeClock(epoch)
+	late := c.NewTimer(2 * time.Minute)
+	early := c.NewTimer(time.Minute)
+	if n := c.Pending(); n != 2 {
+		t.Fatalf("Pending = %d, want 2", n)
+	}
+	if next, ok := c.NextDeadline(); !ok || !next.Equal(epoch.Add(time.Minute)) {
+		t.Errorf("NextDeadline = %v, %v, want %v", next, ok, epoch.Add(time.Minute))
+	}
+
+	c.Advance(59 * time.Second)
+	select {
+	case <-early.C():
+		t.Fatal("timer fired before its deadline")
+	default:
+	}
+
+	c.Advance(time.Second)
+	select {
+	case at := <-early.C():
+		if !at.Equal(epoch.Add(time.Minute)) {
+			t.Errorf("timer fired with %v, want %v", at, epoch.Add(time.Minute))
+		}
+	default:
+		t.Fatal("timer did not fire at its deadline")
+	}
+	if n := c.Pending(); n != 1 {
+		t.Errorf("Pending = %d, want 1", n)
+	}
+
+	// A jump past several deadlines fires every timer on the way
+	c.Advance(time.Hour)
+	select {
+	case <-late.C():
+	default:
+		t.Fatal("timer did not fire when the clock jumped past it")
+	}
+	if _, ok := c.NextDeadline(); ok {
+		t.Error("NextDeadline reports a timer after all fired")
+	}
+}
+
+func TestFakeClockTimerStop(t *testing.T) {
+	c := NewFakeClock(epoch)
+	timer := c.NewTimer(time.Minute)
+	if !timer.Stop() {
+		t.Error("Stop of a pending timer = false")
+	}
+	if timer.Stop() {
+		t.Error("second Stop = true")
+	}
+	c.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Error("stopped timer fired")
+	default:
+	}
+}
+
+func TestFakeClockImmediateTimer(t *testing.T) {
+	c := NewFakeClock(epoch)
+	timer := c.NewTimer(0)
+	select {
+	case <-timer.C():
+	default:
+		t.Fatal("timer of zero duration did not fire at once")
+	}
+	if n := c.Pending(); n != 0 {
+		t.Errorf("Pending = %d, want 0", n)
+	}
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	c := NewFakeClock(epoch)
+	done := make(chan struct{})
+	go func() {
+		c.BlockUntil(2)
+		close(done)
+	}()
+
+	c.NewTimer(time.Minute)
+	select {
+	case <-done:
+		t.Fatal("BlockUntil(2) returned with one timer pending")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	c.NewTimer(time.Minute)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("BlockUntil(2) did not return with two timers pending")
+	}
+}
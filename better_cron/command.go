@@ -0,0 +1,151 @@
+package better_cron
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// defaultMaxOutput caps how much of each output stream a command keeps
+const defaultMaxOutput = 1 << 20
+
+// CommandResult is the run result recorded for a Command
+type CommandResult struct {
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	// Truncated is set when an output stream exceeded MaxOutput
+	Truncated bool
+	// CPUTime is the user and system CPU time of the process
+	CPUTime time.Duration
+	// Signal names the signal that ended the process, e.g. "killed"
+	Signal string
+}
+
+// Command is a job that executes an OS command under the run context, so it
+// is killed when the run times out or the scheduler shuts down. Args and
+// Env are expanded with ExpandTemplate for every run.
+type Command struct {
+	Path string
+	Args []string
+	Dir  string
+	Env  []string
+	// MaxOutput caps the bytes kept per stream; 0 means 1 MiB
+	MaxOutput int
+	// WaitDelay bounds how long to wait for output after the process is
+	// killed; 0 means 5 seconds
+	WaitDelay time.Duration
+	// Limits constrains the process's resources
+	Limits *ResourceLimits
+}
+
+// CommandJob creates a job running name with args, e.g.
+// CommandJob("sh", "-c", "pg_dump mydb > /backups/db.sql")
+func CommandJob(name string, args ...string) *Command {
+	return &Command{Path: name, Args: args}
+}
+
+// Run executes the command with a background context
+func (c *Command) Run() { _, _ = c.RunResult(context.Background()) }
+
+// RunResult executes the command and returns its CommandResult. A non-zero
+// exit code is reported as an error.
+func (c *Command) RunResult(ctx context.Context) (interface{}, error) {
+	limit := c.MaxOutput
+	if limit <= 0 {
+		limit = defaultMaxOutput
+	}
+
+	path, args := c.Path, c.Args
+	args, err := expandAll(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("command %s: %w", c.Path, err)
+	}
+	env, err := expandAll(ctx, c.Env)
+	if err != nil {
+		return nil, fmt.Errorf("command %s: %w", c.Path, err)
+	}
+	if c.Limits != nil {
+		var err error
+		if path, args, err = limitCommand(c.Limits, path, args); err != nil {
+			return nil, fmt.Errorf("command %s: %w", c.Path, err)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	setProcessGroup(cmd)
+	leaveCgroup, err := joinCgroup(cmd, c.Limits)
+	if err != nil {
+		return nil, fmt.Errorf("command %s: %w", c.Path, err)
+	}
+	defer leaveCgroup()
+	cmd.Dir = c.Dir
+	if c.Env != nil {
+		cmd.Env = env
+	}
+	cmd.WaitDelay = c.WaitDelay
+	if cmd.WaitDelay <= 0 {
+		cmd.WaitDelay = 5 * time.Second
+	}
+
+	stdout := &limitedBuffer{limit: limit}
+	stderr := &limitedBuffer{limit: limit}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	result := CommandResult{
+		ExitCode:  -1,
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Truncated: stdout.truncated || stderr.truncated,
+	}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+		result.CPUTime = cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()
+		result.Signal = exitSignal(cmd.ProcessState)
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return result, fmt.Errorf("command %s killed: %w", c.Path, ctx.Err())
+	case errors.As(err, &exitErr):
+		return result, fmt.Errorf("command %s exited with code %d", c.Path, result.ExitCode)
+	case err != nil:
+		return result, fmt.Errorf("command %s: %w", c.Path, err)
+	}
+	return result, nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
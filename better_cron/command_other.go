@@ -0,0 +1,15 @@
+//go:build !unix
+
+package better_cron
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op where process groups are not supported; only
+// the command itself is killed on cancellation
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// exitSignal reports no signal where processes are not ended by signals
+func exitSignal(state *os.ProcessState) string { return "" }
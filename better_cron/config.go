@@ -0,0 +1,705 @@
+package better_cron
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration written as a string such as "90s" or "1h30m"
+// in job definition files
+type Duration time.Duration
+
+// UnmarshalText parses a duration string
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration as a string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// JobsFile is the top-level document of a job definition file
+type JobsFile struct {
+	Jobs []JobDefinition `yaml:"jobs" json:"jobs" toml:"jobs"`
+}
+
+// JobDefinition declares a single job in a job definition file
+type JobDefinition struct {
+	Name     string `yaml:"name" json:"name" toml:"name"`
+	Spec     string `yaml:"spec" json:"spec" toml:"spec"`
+	Type     string `yaml:"type" json:"type" toml:"type"`
+	Timezone string `yaml:"timezone" json:"timezone" toml:"timezone"`
+	// Windows restrict when fires may run, e.g. "Mon-Fri 08:00-18:00"
+	AllowedWindows  []string           `yaml:"allowed_windows" json:"allowed_windows" toml:"allowed_windows"`
+	BlackoutWindows []string           `yaml:"blackout_windows" json:"blackout_windows" toml:"blackout_windows"`
+	Timeout         Duration           `yaml:"timeout" json:"timeout" toml:"timeout"`
+	GracePeriod     Duration           `yaml:"grace_period" json:"grace_period" toml:"grace_period"`
+	RunOnStart      bool               `yaml:"run_on_start" json:"run_on_start" toml:"run_on_start"`
+	MaxRuns         int                `yaml:"max_runs" json:"max_runs" toml:"max_runs"`
+	EndAt           time.Time          `yaml:"end_at" json:"end_at" toml:"end_at"`
+	Retries         *RetryDefinition   `yaml:"retries" json:"retries" toml:"retries"`
+	Overlap         *OverlapDefinition `yaml:"overlap" json:"overlap" toml:"overlap"`
+	Breaker         *BreakerDefinition `yaml:"breaker" json:"breaker" toml:"breaker"`
+	// Jitter delays each fire by a random splay up to this, see WithJitter
+	Jitter Duration `yaml:"jitter" json:"jitter" toml:"jitter"`
+	// Heartbeat lets runs extend their timeout, see WithHeartbeat
+	Heartbeat     Duration          `yaml:"heartbeat" json:"heartbeat" toml:"heartbeat"`
+	Budget        *BudgetDefinition `yaml:"budget" json:"budget" toml:"budget"`
+	SLA           *SLADefinition    `yaml:"sla" json:"sla" toml:"sla"`
+	Tags          []string          `yaml:"tags" json:"tags" toml:"tags"`
+	Params        map[string]string `yaml:"params" json:"params" toml:"params"`
+	Notifications []string          `yaml:"notifications" json:"notifications" toml:"notifications"`
+
+	// RerunInterrupted re-runs runs a crash cut off, see WithRunJournal
+	RerunInterrupted bool `yaml:"rerun_interrupted" json:"rerun_interrupted" toml:"rerun_interrupted"`
+	// Namespace registers the job as namespace/name
+	Namespace string `yaml:"namespace" json:"namespace" toml:"namespace"`
+	// FailureThreshold pauses the job after that many failures in a row
+	FailureThreshold int `yaml:"failure_threshold" json:"failure_threshold" toml:"failure_threshold"`
+	// ScheduleBackoff stretches the schedule of a failing job up to this
+	ScheduleBackoff Duration `yaml:"schedule_backoff" json:"schedule_backoff" toml:"schedule_backoff"`
+	// Upstreams names the jobs this one depends on, see WithUpstream
+	Upstreams []string `yaml:"upstreams" json:"upstreams" toml:"upstreams"`
+
+	// Func names a job registered with RegisterJobFunc, for types "func"
+	// and "isolated"
+	Func    string             `yaml:"func" json:"func" toml:"func"`
+	Command *CommandDefinition `yaml:"command" json:"command" toml:"command"`
+	HTTP    *HTTPDefinition    `yaml:"http" json:"http" toml:"http"`
+	GRPC    *GRPCDefinition    `yaml:"grpc" json:"grpc" toml:"grpc"`
+	Docker  *DockerDefinition  `yaml:"docker" json:"docker" toml:"docker"`
+	// Options carries settings for job types added with RegisterJobType
+	Options map[string]interface{} `yaml:"options" json:"options" toml:"options"`
+}
+
+// RetryDefinition declares the retry policy of a job
+type RetryDefinition struct {
+	Attempts   int      `yaml:"attempts" json:"attempts" toml:"attempts"`
+	Backoff    Duration `yaml:"backoff" json:"backoff" toml:"backoff"`
+	MaxBackoff Duration `yaml:"max_backoff" json:"max_backoff" toml:"max_backoff"`
+}
+
+// OverlapDefinition declares how a job handles fires that overlap a running
+// run. Policy is "allow", "skip" or "queue"; QueueDepth and Overflow,
+// "drop_newest" or "drop_oldest", apply to the queue.
+type OverlapDefinition struct {
+	Policy       string `yaml:"policy" json:"policy" toml:"policy"`
+	MaxInstances int    `yaml:"max_instances" json:"max_instances" toml:"max_instances"`
+	QueueDepth   int    `yaml:"queue_depth" json:"queue_depth" toml:"queue_depth"`
+	Overflow     string `yaml:"overflow" json:"overflow" toml:"overflow"`
+}
+
+// overlapPolicies and overflowPolicies are the policies by their names in
+// job definitions
+var (
+	overlapPolicies  = map[string]OverlapPolicy{"allow": OverlapAllow, "skip": OverlapSkip, "queue": OverlapQueue}
+	overflowPolicies = map[string]OverflowPolicy{"drop_newest": DropNewest, "drop_oldest": DropOldest}
+)
+
+// BreakerDefinition declares the circuit breaker of a job, see
+// WithCircuitBreaker
+type BreakerDefinition struct {
+	Threshold int      `yaml:"threshold" json:"threshold" toml:"threshold"`
+	Cooldown  Duration `yaml:"cooldown" json:"cooldown" toml:"cooldown"`
+}
+
+// BudgetDefinition declares the execution budget of a job, e.g. a limit of
+// 30m per 24h window
+type BudgetDefinition struct {
+	Limit  Duration `yaml:"limit" json:"limit" toml:"limit"`
+	Window Duration `yaml:"window" json:"window" toml:"window"`
+}
+
+// SLADefinition declares the completion deadlines of a job, e.g. a
+// deadline of "0 0 6 * * *" with a 1h warning
+type SLADefinition struct {
+	Deadline string   `yaml:"deadline" json:"deadline" toml:"deadline"`
+	Warning  Duration `yaml:"warning" json:"warning" toml:"warning"`
+}
+
+// CommandDefinition declares a job of type "command"
+type CommandDefinition struct {
+	Args   []string          `yaml:"args" json:"args" toml:"args"`
+	Dir    string            `yaml:"dir" json:"dir" toml:"dir"`
+	Env    []string          `yaml:"env" json:"env" toml:"env"`
+	Limits *LimitsDefinition `yaml:"limits" json:"limits" toml:"limits"`
+}
+
+// LimitsDefinition declares the resource limits of a command's process
+type LimitsDefinition struct {
+	MaxMemory    int64  `yaml:"max_memory" json:"max_memory" toml:"max_memory"`
+	CPUWeight    int    `yaml:"cpu_weight" json:"cpu_weight" toml:"cpu_weight"`
+	MaxOpenFiles uint64 `yaml:"max_open_files" json:"max_open_files" toml:"max_open_files"`
+	Nice         int    `yaml:"nice" json:"nice" toml:"nice"`
+	Cgroup       string `yaml:"cgroup" json:"cgroup" toml:"cgroup"`
+}
+
+// HTTPDefinition declares a job of type "http"
+type HTTPDefinition struct {
+	Method         string            `yaml:"method" json:"method" toml:"method"`
+	URL            string            `yaml:"url" json:"url" toml:"url"`
+	Headers        map[string]string `yaml:"headers" json:"headers" toml:"headers"`
+	Body           string            `yaml:"body" json:"body" toml:"body"`
+	ExpectedStatus []int             `yaml:"expected_status" json:"expected_status" toml:"expected_status"`
+}
+
+// GRPCDefinition declares a job of type "grpc"
+type GRPCDefinition struct {
+	Target  string `yaml:"target" json:"target" toml:"target"`
+	Method  string `yaml:"method" json:"method" toml:"method"`
+	Request string `yaml:"request" json:"request" toml:"request"`
+}
+
+// DockerDefinition declares a job of type "docker"
+type DockerDefinition struct {
+	Image  string   `yaml:"image" json:"image" toml:"image"`
+	Cmd    []string `yaml:"cmd" json:"cmd" toml:"cmd"`
+	Env    []string `yaml:"env" json:"env" toml:"env"`
+	Memory int64    `yaml:"memory" json:"memory" toml:"memory"`
+	CPUs   float64  `yaml:"cpus" json:"cpus" toml:"cpus"`
+	Host   string   `yaml:"host" json:"host" toml:"host"`
+}
+
+// JobFactory builds the job body of a definition for a registered job type
+type JobFactory func(def JobDefinition) (cron.Job, error)
+
+var jobTypes = struct {
+	sync.RWMutex
+	factories map[string]JobFactory
+}{
+	factories: map[string]JobFactory{
+		"command":  commandFactory,
+		"http":     httpFactory,
+		"grpc":     grpcFactory,
+		"docker":   dockerFactory,
+		"func":     funcFactory,
+		"isolated": isolatedFactory,
+	},
+}
+
+var jobFuncs = struct {
+	sync.RWMutex
+	jobs map[string]cron.Job
+}{
+	jobs: make(map[string]cron.Job),
+}
+
+// RegisterJobType makes a job type available to job definition files
+func RegisterJobType(name string, factory JobFactory) {
+	jobTypes.Lock()
+	defer jobTypes.Unlock()
+	jobTypes.factories[name] = factory
+}
+
+// RegisterJobFunc registers a Go job under a name that definitions of type
+// "func" can refer to, so schedules can change without code changes
+func RegisterJobFunc(name string, job cron.Job) {
+	jobFuncs.Lock()
+	defer jobFuncs.Unlock()
+	jobFuncs.jobs[name] = job
+}
+
+// ConfigError lists every problem found in a job definition file
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid job configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// LoadJobsFromFile parses a job definition file and registers every job it
+// declares. The format is chosen by extension: .yaml or .yml, .json, or
+// .toml. The whole file is validated before any job is added.
+//
+// Loading a path again reconciles the scheduler against the file: new jobs
+// are added, changed ones updated and jobs no longer in the file removed.
+// Jobs registered in code or from other files are left alone, and runs in
+// flight are never interrupted.
+func (ec *EnhancedCron) LoadJobsFromFile(path string) error {
+	return ec.loadJobsFile(path, ActorAPI)
+}
+
+// loadJobsFile loads path on behalf of actor, recording reloads of a file
+// loaded before as admin actions
+func (ec *EnhancedCron) loadJobsFile(path, actor string) error {
+	ec.configMu.Lock()
+	_, reload := ec.configDefs[path]
+	ec.configMu.Unlock()
+
+	err := ec.readJobsFile(path)
+	if reload {
+		ec.recordAction(AdminAction{Actor: actor, Action: "reload", Target: path}, err)
+	}
+	return err
+}
+
+// readJobsFile reconciles the registered jobs with the definitions in path
+func (ec *EnhancedCron) readJobsFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := decodeJobsFile(path, data)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return ec.reconcileDefinitions(path, file.Jobs)
+}
+
+// decodeJobsFile decodes data in the format implied by the path's extension,
+// rejecting unknown fields in every format
+func decodeJobsFile(path string, data []byte) (JobsFile, error) {
+	var file JobsFile
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&file); err != nil {
+			return file, err
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&file); err != nil {
+			return file, err
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), &file)
+		if err != nil {
+			return file, err
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			return file, fmt.Errorf("unknown fields: %s", strings.Join(keys, ", "))
+		}
+	default:
+		return file, fmt.Errorf("unsupported job file format %q", ext)
+	}
+	return file, nil
+}
+
+// builtJob is a validated definition together with its job body and options
+type builtJob struct {
+	def  JobDefinition
+	job  cron.Job
+	opts []JobOption
+}
+
+// buildDefinitions validates and builds all definitions, reporting every
+// problem at once
+func (ec *EnhancedCron) buildDefinitions(defs []JobDefinition) ([]builtJob, error) {
+	var problems []string
+	var jobs []builtJob
+	seen := make(map[string]bool)
+	for i, def := range defs {
+		label := fmt.Sprintf("jobs[%d]", i)
+		name := def.qualifiedName()
+		if def.Name != "" {
+			label = fmt.Sprintf("jobs[%d] (%s)", i, name)
+		}
+
+		errs := ec.validateDefinition(def)
+		if def.Name != "" && seen[name] {
+			errs = append(errs, "duplicate job name")
+		}
+		seen[name] = true
+		if len(errs) > 0 {
+			for _, e := range errs {
+				problems = append(problems, label+": "+e)
+			}
+			continue
+		}
+
+		job, opts, err := ec.buildDefinition(def)
+		if err != nil {
+			problems = append(problems, label+": "+err.Error())
+			continue
+		}
+		jobs = append(jobs, builtJob{def, job, opts})
+	}
+	if len(problems) > 0 {
+		return nil, &ConfigError{Problems: problems}
+	}
+	return jobs, nil
+}
+
+// reconcileDefinitions brings the jobs registered from source in line with defs
+func (ec *EnhancedCron) reconcileDefinitions(source string, defs []JobDefinition) error {
+	jobs, err := ec.buildDefinitions(defs)
+	if err != nil {
+		return err
+	}
+
+	ec.configMu.Lock()
+	defer ec.configMu.Unlock()
+
+	actor := WithAuditActor("config:" + source)
+	previous := ec.configDefs[source]
+	current := make(map[string]JobDefinition, len(jobs))
+	var added, updated, removed int
+
+	for _, b := range jobs {
+		name := b.def.qualifiedName()
+		current[name] = b.def
+
+		// Jobs past their end time stay in the file but are not registered
+		if !b.def.EndAt.IsZero() && !ec.clock.Now().Before(b.def.EndAt) {
+			continue
+		}
+
+		old, known := previous[name]
+		switch {
+		case !known:
+			if _, err := ec.AddJob(b.def.Spec, b.job, name, append(b.opts, actor)...); err != nil {
+				return fmt.Errorf("add job %s: %w", name, err)
+			}
+			added++
+		case !reflect.DeepEqual(old, b.def):
+			// The job may have gone since the last load, after its last
+			// run or through RemoveJob, so the new definition adds it back
+			ec.mu.RLock()
+			_, exists := ec.jobs[name]
+			ec.mu.RUnlock()
+			if !exists {
+				if _, err := ec.AddJob(b.def.Spec, b.job, name, append(b.opts, actor)...); err != nil {
+					return fmt.Errorf("add job %s: %w", name, err)
+				}
+				added++
+				break
+			}
+			if _, err := ec.UpdateJob(b.def.Spec, b.job, name, append(b.opts, actor)...); err != nil {
+				return fmt.Errorf("update job %s: %w", name, err)
+			}
+			updated++
+		}
+		// Record progress so a failure part way leaves consistent state
+		ec.configDefs[source] = mergeDefinitions(previous, current)
+	}
+
+	for name := range previous {
+		if _, keep := current[name]; keep {
+			continue
+		}
+		if err := ec.removeJob(name, "config:"+source); err != nil {
+			ec.logger.Error("remove job %s: %v", name, err)
+		}
+		removed++
+	}
+
+	ec.configDefs[source] = current
+	if previous != nil {
+		ec.logger.Info("reloaded %s: %d added, %d updated, %d removed", source, added, updated, removed)
+	}
+	return nil
+}
+
+// mergeDefinitions overlays current onto previous
+func mergeDefinitions(previous, current map[string]JobDefinition) map[string]JobDefinition {
+	merged := make(map[string]JobDefinition, len(previous)+len(current))
+	for name, def := range previous {
+		merged[name] = def
+	}
+	for name, def := range current {
+		merged[name] = def
+	}
+	return merged
+}
+
+// parseTimeWindows parses the windows of a definition
+func parseTimeWindows(specs []string) ([]TimeWindow, error) {
+	var windows []TimeWindow
+	for _, spec := range specs {
+		w, err := ParseTimeWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// qualifiedName is the name the definition's job is registered under
+func (def JobDefinition) qualifiedName() string {
+	return QualifiedName(def.Namespace, def.Name)
+}
+
+// validateDefinition checks the fields shared by every job type
+func (ec *EnhancedCron) validateDefinition(def JobDefinition) []string {
+	var errs []string
+	if def.Name == "" {
+		errs = append(errs, "name is required")
+	}
+	if strings.Contains(def.Namespace, namespaceSeparator) {
+		errs = append(errs, fmt.Sprintf("namespace must not contain %q", namespaceSeparator))
+	}
+	if def.Spec == "" {
+		errs = append(errs, "spec is required")
+	} else if _, err := ValidateSpec(def.Spec); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if def.Timezone != "" {
+		if _, err := time.LoadLocation(def.Timezone); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid timezone %q: %v", def.Timezone, err))
+		}
+	}
+	for _, w := range append(append([]string(nil), def.AllowedWindows...), def.BlackoutWindows...) {
+		if _, err := ParseTimeWindow(w); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if def.Timeout < 0 {
+		errs = append(errs, "timeout must not be negative")
+	}
+	if def.GracePeriod < 0 {
+		errs = append(errs, "grace_period must not be negative")
+	}
+	if def.MaxRuns < 0 {
+		errs = append(errs, "max_runs must not be negative")
+	}
+	if def.FailureThreshold < 0 {
+		errs = append(errs, "failure_threshold must not be negative")
+	}
+	if def.ScheduleBackoff < 0 {
+		errs = append(errs, "schedule_backoff must not be negative")
+	}
+	if def.Budget != nil && (def.Budget.Limit <= 0 || def.Budget.Window <= 0) {
+		errs = append(errs, "budget.limit and budget.window must be positive")
+	}
+	if def.SLA != nil {
+		if _, err := ValidateSpec(def.SLA.Deadline); err != nil {
+			errs = append(errs, "sla.deadline: "+err.Error())
+		}
+		if def.SLA.Warning < 0 {
+			errs = append(errs, "sla.warning must not be negative")
+		}
+	}
+	if def.Retries != nil && def.Retries.Attempts < 1 {
+		errs = append(errs, "retries.attempts must be at least 1")
+	}
+	if o := def.Overlap; o != nil {
+		if _, ok := overlapPolicies[o.Policy]; !ok && o.Policy != "" {
+			errs = append(errs, fmt.Sprintf("unknown overlap.policy %q", o.Policy))
+		}
+		if _, ok := overflowPolicies[o.Overflow]; !ok && o.Overflow != "" {
+			errs = append(errs, fmt.Sprintf("unknown overlap.overflow %q", o.Overflow))
+		}
+		if o.MaxInstances < 0 {
+			errs = append(errs, "overlap.max_instances must not be negative")
+		}
+		if o.Policy == "queue" && o.QueueDepth < 1 {
+			errs = append(errs, "overlap.queue_depth must be at least 1")
+		}
+	}
+	if def.Breaker != nil && (def.Breaker.Threshold < 1 || def.Breaker.Cooldown < 0) {
+		errs = append(errs, "breaker.threshold must be at least 1 and breaker.cooldown not negative")
+	}
+	if def.Jitter < 0 {
+		errs = append(errs, "jitter must not be negative")
+	}
+	if def.Heartbeat < 0 {
+		errs = append(errs, "heartbeat must not be negative")
+	}
+	for _, name := range def.Notifications {
+		if _, ok := ec.notifiers[name]; !ok {
+			errs = append(errs, fmt.Sprintf("unknown notifier %q", name))
+		}
+	}
+
+	jobTypes.RLock()
+	_, known := jobTypes.factories[def.Type]
+	jobTypes.RUnlock()
+	if def.Type == "" {
+		errs = append(errs, "type is required")
+	} else if !known {
+		errs = append(errs, fmt.Sprintf("unknown job type %q", def.Type))
+	}
+	return errs
+}
+
+// buildDefinition creates the job body and options of a valid definition
+func (ec *EnhancedCron) buildDefinition(def JobDefinition) (cron.Job, []JobOption, error) {
+	jobTypes.RLock()
+	factory := jobTypes.factories[def.Type]
+	jobTypes.RUnlock()
+
+	job, err := factory(def)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var opts []JobOption
+	if def.Timezone != "" {
+		loc, err := time.LoadLocation(def.Timezone)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, WithTimezone(loc))
+	}
+	allowed, err := parseTimeWindows(def.AllowedWindows)
+	if err != nil {
+		return nil, nil, err
+	}
+	blackouts, err := parseTimeWindows(def.BlackoutWindows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(allowed) > 0 {
+		opts = append(opts, WithAllowedWindows(allowed...))
+	}
+	if len(blackouts) > 0 {
+		opts = append(opts, WithBlackoutWindows(blackouts...))
+	}
+	if def.Timeout > 0 {
+		opts = append(opts, WithJobTimeout(time.Duration(def.Timeout)))
+	}
+	if def.GracePeriod > 0 {
+		opts = append(opts, WithGracePeriod(time.Duration(def.GracePeriod)))
+	}
+	if def.RunOnStart {
+		opts = append(opts, WithRunOnStart())
+	}
+	if def.RerunInterrupted {
+		opts = append(opts, WithRerunInterrupted())
+	}
+	if def.MaxRuns > 0 {
+		opts = append(opts, WithMaxRuns(def.MaxRuns))
+	}
+	if def.FailureThreshold > 0 {
+		opts = append(opts, WithFailureThreshold(def.FailureThreshold))
+	}
+	if def.ScheduleBackoff > 0 {
+		opts = append(opts, WithScheduleBackoff(time.Duration(def.ScheduleBackoff)))
+	}
+	if !def.EndAt.IsZero() {
+		opts = append(opts, WithEndAt(def.EndAt))
+	}
+	if def.Budget != nil {
+		opts = append(opts, WithExecutionBudget(time.Duration(def.Budget.Limit), time.Duration(def.Budget.Window)))
+	}
+	if def.SLA != nil {
+		opts = append(opts, WithSLA(def.SLA.Deadline, time.Duration(def.SLA.Warning)))
+	}
+	if def.Retries != nil {
+		backoff := time.Duration(def.Retries.Backoff)
+		maxBackoff := time.Duration(def.Retries.MaxBackoff)
+		if maxBackoff == 0 {
+			maxBackoff = backoff * 32
+		}
+		opts = append(opts, WithRetry(def.Retries.Attempts, ExponentialBackoff(backoff, maxBackoff)))
+	}
+	if o := def.Overlap; o != nil {
+		if policy := overlapPolicies[o.Policy]; policy == OverlapQueue {
+			opts = append(opts, WithQueue(o.QueueDepth, overflowPolicies[o.Overflow]))
+		} else {
+			opts = append(opts, WithOverlapPolicy(policy))
+		}
+		if o.MaxInstances > 0 {
+			opts = append(opts, WithMaxInstances(o.MaxInstances))
+		}
+	}
+	if def.Breaker != nil {
+		opts = append(opts, WithCircuitBreaker(def.Breaker.Threshold, time.Duration(def.Breaker.Cooldown)))
+	}
+	if def.Jitter > 0 {
+		opts = append(opts, WithJitter(time.Duration(def.Jitter)))
+	}
+	if def.Heartbeat > 0 {
+		opts = append(opts, WithHeartbeat(time.Duration(def.Heartbeat)))
+	}
+	if len(def.Tags) > 0 {
+		opts = append(opts, WithTags(def.Tags...))
+	}
+	if len(def.Params) > 0 {
+		opts = append(opts, WithParams(def.Params))
+	}
+	if len(def.Upstreams) > 0 {
+		opts = append(opts, WithUpstream(def.Upstreams...))
+	}
+	for _, name := range def.Notifications {
+		opts = append(opts, WithNotifier(ec.notifiers[name]))
+	}
+	return job, opts, nil
+}
+
+func commandFactory(def JobDefinition) (cron.Job, error) {
+	if def.Command == nil || len(def.Command.Args) == 0 {
+		return nil, fmt.Errorf("command.args is required")
+	}
+	cmd := CommandJob(def.Command.Args[0], def.Command.Args[1:]...)
+	cmd.Dir = def.Command.Dir
+	cmd.Env = def.Command.Env
+	if l := def.Command.Limits; l != nil {
+		cmd.Limits = &ResourceLimits{MaxMemory: l.MaxMemory, CPUWeight: l.CPUWeight, MaxOpenFiles: l.MaxOpenFiles, Nice: l.Nice, Cgroup: l.Cgroup}
+		if err := cmd.Limits.validate(); err != nil {
+			return nil, fmt.Errorf("command.limits: %w", err)
+		}
+	}
+	return cmd, nil
+}
+
+func httpFactory(def JobDefinition) (cron.Job, error) {
+	if def.HTTP == nil || def.HTTP.URL == "" {
+		return nil, fmt.Errorf("http.url is required")
+	}
+	return &HTTPJob{
+		Method:         def.HTTP.Method,
+		URL:            def.HTTP.URL,
+		Headers:        def.HTTP.Headers,
+		Body:           def.HTTP.Body,
+		ExpectedStatus: def.HTTP.ExpectedStatus,
+	}, nil
+}
+
+func grpcFactory(def JobDefinition) (cron.Job, error) {
+	if def.GRPC == nil || def.GRPC.Target == "" || def.GRPC.Method == "" {
+		return nil, fmt.Errorf("grpc.target and grpc.method are required")
+	}
+	return &GRPCJob{Target: def.GRPC.Target, Method: def.GRPC.Method, Request: def.GRPC.Request}, nil
+}
+
+func dockerFactory(def JobDefinition) (cron.Job, error) {
+	if def.Docker == nil || def.Docker.Image == "" {
+		return nil, fmt.Errorf("docker.image is required")
+	}
+	return &DockerJob{
+		Image:  def.Docker.Image,
+		Cmd:    def.Docker.Cmd,
+		Env:    def.Docker.Env,
+		Memory: def.Docker.Memory,
+		CPUs:   def.Docker.CPUs,
+		Host:   def.Docker.Host,
+	}, nil
+}
+
+func funcFactory(def JobDefinition) (cron.Job, error) {
+	jobFuncs.RLock()
+	job, ok := jobFuncs.jobs[def.Func]
+	jobFuncs.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no job func registered as %q", def.Func)
+	}
+	return job, nil
+}
@@ -0,0 +1,143 @@
+package better_cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// contextKey is the type of keys for values better_cron stores in run contexts
+type contextKey int
+
+const (
+	attemptKey contextKey = iota
+	loggerKey
+	heartbeatKey
+	reporterKey
+	identityKey
+	principalKey
+)
+
+// runIdentity is the identity of a run, fixed when it starts
+type runIdentity struct {
+	job       string
+	runID     string
+	scheduled time.Time
+	trigger   string
+	params    map[string]string
+}
+
+// withRunIdentity stores the identity of the run in ctx
+func withRunIdentity(ctx context.Context, metadata *JobMetadata) context.Context {
+	return context.WithValue(ctx, identityKey, runIdentity{
+		job:       metadata.Name,
+		runID:     metadata.RunID,
+		scheduled: metadata.ScheduledTime,
+		trigger:   metadata.Trigger,
+		params:    metadata.Params,
+	})
+}
+
+// JobNameFromContext returns the name of the job the current run belongs
+// to, or "" if ctx does not belong to a run
+func JobNameFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(identityKey).(runIdentity)
+	return id.job
+}
+
+// RunIDFromContext returns the ID of the current run, or "" if ctx does not
+// belong to a run
+func RunIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(identityKey).(runIdentity)
+	return id.runID
+}
+
+// ScheduledTimeFromContext returns when the current run's fire was due, as
+// JobMetadata.ScheduledTime, or the zero time if ctx does not belong to a
+// run
+func ScheduledTimeFromContext(ctx context.Context) time.Time {
+	id, _ := ctx.Value(identityKey).(runIdentity)
+	return id.scheduled
+}
+
+// DeadlineFromContext returns when the current run will be cancelled for
+// running too long. Unlike ctx.Deadline, it follows deadlines extended by
+// Heartbeat.
+func DeadlineFromContext(ctx context.Context) (time.Time, bool) {
+	if hb, ok := ctx.Value(heartbeatKey).(*heartbeat); ok {
+		hb.mu.Lock()
+		defer hb.mu.Unlock()
+		return hb.deadline, true
+	}
+	return ctx.Deadline()
+}
+
+// AttemptFromContext returns the 1-based attempt number of the current run,
+// or 0 if ctx does not belong to a run
+func AttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey).(int)
+	return attempt
+}
+
+// LoggerFromContext returns the logger of the current run, which tags every
+// line with the job name, run ID and attempt. Outside a run it returns a
+// logger that discards everything.
+func LoggerFromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(loggerKey).(Logger); ok {
+		return logger
+	}
+	return nopLogger{}
+}
+
+// FieldsLogger is implemented by loggers that take structured fields, such
+// as custom_logger.Logger. Run loggers pass their fields this way instead
+// of prefixing the message.
+type FieldsLogger interface {
+	Logger
+	InfoFields(fields map[string]interface{}, msg string, args ...interface{})
+	ErrorFields(fields map[string]interface{}, msg string, args ...interface{})
+}
+
+// runLogger binds the identity of one run attempt to a base logger
+type runLogger struct {
+	base   Logger
+	fields map[string]interface{}
+	prefix string
+}
+
+// runLoggerBase returns the logger that the job's run loggers build on
+func (ec *EnhancedCron) runLoggerBase(entry *jobEntry) Logger {
+	if entry.cfg.logger != nil {
+		return entry.cfg.logger
+	}
+	return ec.logger
+}
+
+// newRunLogger derives the logger handed to one attempt of a run
+func newRunLogger(base Logger, metadata *JobMetadata) Logger {
+	return &runLogger{
+		base: base,
+		fields: map[string]interface{}{
+			"job":     metadata.Name,
+			"run_id":  metadata.RunID,
+			"attempt": metadata.Attempt,
+		},
+		prefix: fmt.Sprintf("job=%s run_id=%s attempt=%d: ", metadata.Name, metadata.RunID, metadata.Attempt),
+	}
+}
+
+func (l *runLogger) Info(msg string, args ...interface{}) {
+	if fl, ok := l.base.(FieldsLogger); ok {
+		fl.InfoFields(l.fields, msg, args...)
+		return
+	}
+	l.base.Info("%s%s", l.prefix, fmt.Sprintf(msg, args...))
+}
+
+func (l *runLogger) Error(msg string, args ...interface{}) {
+	if fl, ok := l.base.(FieldsLogger); ok {
+		fl.ErrorFields(l.fields, msg, args...)
+		return
+	}
+	l.base.Error("%s%s", l.prefix, fmt.Sprintf(msg, args...))
+}
@@ -3,7 +3,10 @@ package better_cron
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -20,46 +23,254 @@ const (
 	StatusCancelled
 )
 
+// Convert JobStatus to string
+func (s JobStatus) String() string {
+	return [...]string{"idle", "running", "completed", "failed", "cancelled"}[s]
+}
+
 // JobMetadata contains information about a job execution
 type JobMetadata struct {
 	ID        cron.EntryID
+	RunID     string
 	Name      string
 	StartTime time.Time
 	EndTime   time.Time
 	Status    JobStatus
 	Error     error
+	Attempt   int
+	// ScheduledTime is the slot the fire was due at, even if the scheduler
+	// fired it late, or when the fire was asked for if it is not from the
+	// schedule; StartTime is later when the run waited for jitter, a queue
+	// or the rate limiter
+	ScheduledTime time.Time
+	// Stack holds the stack trace of the last attempt that panicked, or the
+	// goroutine stacks an orphaned run was hung in
+	Stack []byte
+	// Orphaned is set when the run did not return after its context ended
+	// and the scheduler stopped waiting for it
+	Orphaned bool
+	// Result is the payload returned by a ResultJob
+	Result interface{}
+	// Resources is the run's usage when WithResourceTracking is set
+	Resources ResourceUsage
+	// Progress is what the run last reported through its Reporter
+	Progress Progress
+	// Trigger is what initiated the run: one of the Trigger constants, or
+	// "admin:" and the caller for runs triggered through the admin API
+	Trigger string
+	// Params are the job's parameters, see WithParams
+	Params map[string]string
+	// Fingerprint is the run's input fingerprint, see WithFingerprint
+	Fingerprint string
+}
+
+// Triggers that initiate a run
+const (
+	TriggerSchedule = "schedule"
+	TriggerStart    = "start"
+	TriggerRestart  = "restart"
+	TriggerRestore  = "restore"
+	TriggerRedrive  = "redrive"
+	TriggerManual   = "manual"
+)
+
+// firing is one fire of a job on its way to becoming a run
+type firing struct {
+	// scheduled is when the fire was due, not when it started
+	scheduled time.Time
+	trigger   string
+
+	// params replace the job's parameters for a re-driven run, and
+	// accepted is called once the fire has passed every check and runs
+	params   map[string]string
+	accepted func()
 }
 
 // EnhancedCron wraps the standard better_cron scheduler with additional features
 type EnhancedCron struct {
-	cron           *cron.Cron
-	activeJobs     sync.Map
-	shutdownCtx    context.Context
-	cancelShutdown context.CancelFunc
-	timeout        time.Duration
-	logger         Logger
+	sched        *scheduler
+	clock        Clock
+	activeJobs   sync.Map
+	orphans      sync.Map
+	life         atomic.Pointer[lifecycle]
+	lifeMu       sync.Mutex
+	timeout      time.Duration
+	hungTimeout  time.Duration
+	logger       Logger
+	sinks        []EventSink
+	notifiers    map[string]EventSink
+	audit        AuditLog
+	store        Store
+	metrics      MetricsRecorder
+	pool         *workerPool
+	poolSize     int
+	poolQueue    int
+	limiter      *tokenBucket
+	panicHandler PanicHandler
+	historyLimit int
+	dryRun       bool
+	readOnly     bool
+
+	trackResources bool
+
+	// instance tells this process's journaled runs from earlier ones
+	journal     bool
+	instance    string
+	recoverOnce sync.Once
+
+	// versionMu serializes the numbering of job versions
+	versionMu sync.Mutex
+	actions   actionLog
+
+	namespaceSlots map[string]chan struct{}
+
+	// canary measures how late the scheduler fires, see WithCanary
+	canary    canaryConfig
+	lastDrift atomic.Int64
+
+	// smoothing staggers co-scheduled jobs, see WithLoadSmoothing
+	smoothing time.Duration
+
+	progressInterval time.Duration
+	progressFunc     func(ShutdownProgress)
+
+	mu   sync.RWMutex
+	jobs map[string]*jobEntry
+
+	// definitions loaded from each job file, for reconciling reloads
+	configMu   sync.Mutex
+	configDefs map[string]map[string]JobDefinition
+}
+
+// jobEntry is the scheduler's record of a registered job. When a job is
+// updated in place, the new entry takes over the old entry's jobState so
+// in-flight runs keep their bookkeeping.
+type jobEntry struct {
+	id       cron.EntryID
+	name     string
+	spec     string
+	location *time.Location
+	schedule cron.Schedule
+	cfg      *jobConfig
+	job      cron.Job
+	run      cron.Job
+	expiry   cron.EntryID
+	slaIDs   []cron.EntryID
+	breaker  *circuitBreaker
+
+	*jobState
+}
+
+// jobState is the runtime bookkeeping of a job, guarded by mu
+type jobState struct {
+	mu      sync.Mutex
+	running int
+	runs    int
+	queue   []firing
+	history []JobMetadata
+
+	// deferred is set while a holiday fire waits for the next business day
+	deferred bool
+
+	// retired is set once Namespace.Shutdown removed the job, dropping its
+	// fires that have yet to start
+	retired bool
+
+	// paused stops scheduled fires; failStreak counts failed runs in a row
+	paused     bool
+	failStreak int
+
+	// backoffLevel is read by the schedule, see WithScheduleBackoff
+	backoffLevel atomic.Int32
+
+	// avgDuration and tight track the pace of runs, see WithTightSchedule;
+	// paceGap is read by the schedule
+	avgDuration time.Duration
+	tight       bool
+	paceGap     atomic.Int64
+
+	// fingerprint is that of the last successful run if fingerprintFound,
+	// and fingerprintLoaded once the store has been consulted for it,
+	// see WithFingerprint
+	fingerprint       string
+	fingerprintFound  bool
+	fingerprintLoaded bool
+
+	// budgetUsed is the run time charged to the budget window at budgetStart
+	budgetStart time.Time
+	budgetUsed  time.Duration
+
+	// lastSuccess is checked against the SLA period that began at
+	// slaPeriodStart
+	lastSuccess    time.Time
+	slaPeriodStart time.Time
 }
 
+// activeJob tracks a run that is currently executing
+type activeJob struct {
+	// metadata is written by the run; only the fields set before it
+	// started may be read through it, the rest through status
+	metadata *JobMetadata
+	status   *runStatus
+	wg       *sync.WaitGroup
+	cancel   context.CancelCauseFunc
+	grace    time.Duration
+	progress *progressReporter
+}
+
+// specParser parses the six-field (with seconds) cron specs used by the scheduler
+var specParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
 // Logger interface for custom logging
 type Logger interface {
 	Info(msg string, args ...interface{})
 	Error(msg string, args ...interface{})
 }
 
+// nopLogger discards everything; it is used until WithLogger is given
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, args ...interface{})  {}
+func (nopLogger) Error(msg string, args ...interface{}) {}
+
 // NewEnhancedCron creates a new instance of EnhancedCron
 func NewEnhancedCron(opts ...Option) *EnhancedCron {
-	ctx, cancel := context.WithCancel(context.Background())
 	ec := &EnhancedCron{
-		cron:           cron.New(cron.WithSeconds()),
-		shutdownCtx:    ctx,
-		cancelShutdown: cancel,
-		timeout:        30 * time.Second, // Default timeout
+		clock:        realClock{},
+		timeout:      30 * time.Second, // Default timeout
+		hungTimeout:  30 * time.Second,
+		logger:       nopLogger{},
+		metrics:      nopMetrics{},
+		poolQueue:    -1,
+		historyLimit: 100,
+		instance:     newID(),
+
+		progressInterval: 5 * time.Second,
+		jobs:             make(map[string]*jobEntry),
+		notifiers:        make(map[string]EventSink),
+		namespaceSlots:   make(map[string]chan struct{}),
+		configDefs:       make(map[string]map[string]JobDefinition),
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(ec)
 	}
+	if ec.journal && ec.store == nil {
+		ec.logger.Error("run journal needs a store, journaling disabled")
+		ec.journal = false
+	}
+	ec.sched = newScheduler(ec.clock, NewCronLogger(ec.logger))
+	ec.sched.observe = ec.readOnly
+	if ec.canary.interval > 0 {
+		ec.scheduleCanary()
+	}
+	ec.life.Store(newLifecycle())
+
+	if ec.poolSize > 0 {
+		ec.pool = newWorkerPool(ec.poolSize, ec.poolQueue, ec.metrics)
+		ec.pool.start(ec.stoppingContext())
+	}
 
 	return ec
 }
@@ -67,7 +278,8 @@ func NewEnhancedCron(opts ...Option) *EnhancedCron {
 // Option represents configuration options for EnhancedCron
 type Option func(*EnhancedCron)
 
-// WithTimeout sets the shutdown timeout
+// WithTimeout sets the default run timeout, and the shutdown deadline used
+// when Shutdown is given a context without one
 func WithTimeout(timeout time.Duration) Option {
 	return func(ec *EnhancedCron) {
 		ec.timeout = timeout
@@ -81,66 +293,540 @@ func WithLogger(logger Logger) Option {
 	}
 }
 
+// JobOption represents per-job configuration options
+type JobOption func(*jobConfig)
+
+// jobConfig holds the per-job settings collected from JobOptions
+type jobConfig struct {
+	tags         []string
+	sinks        []EventSink
+	timeout      time.Duration
+	grace        time.Duration
+	runOnStart   bool
+	logger       Logger
+	maxRuns      int
+	endAt        time.Time
+	maxAttempts  int
+	backoff      BackoffPolicy
+	overlap      OverlapPolicy
+	queueDepth   int
+	overflow     OverflowPolicy
+	maxInstances int
+	jitter       time.Duration
+	location     *time.Location
+	allowed      []TimeWindow
+	blackouts    []TimeWindow
+
+	calendar      Calendar
+	holidayPolicy HolidayPolicy
+
+	breakerThreshold int
+	breakerCooldown  time.Duration
+
+	budget       time.Duration
+	budgetWindow time.Duration
+
+	slaSpec    string
+	slaWarning time.Duration
+
+	heartbeat time.Duration
+
+	actor string
+
+	rerunInterrupted bool
+	failureThreshold int
+	backoffMax       time.Duration
+
+	tightRatio  float64
+	tightPolicy TightPolicy
+
+	preconditions []Precondition
+	finalizers    []Finalizer
+
+	params         map[string]string
+	paramTemplates map[string]*template.Template
+
+	idempotencyKey IdempotencyKeyFunc
+	fingerprint    FingerprintFunc
+
+	upstreams   []string
+	noSmoothing bool
+}
+
+// WithTags attaches tags to a job, which are carried on every event it emits
+func WithTags(tags ...string) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.tags = append(cfg.tags, tags...)
+	}
+}
+
+// WithJobTimeout bounds each run of the job, overriding the scheduler timeout
+func WithJobTimeout(timeout time.Duration) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.timeout = timeout
+	}
+}
+
+// WithJobLogger sets the logger handed to the job's runs through
+// LoggerFromContext, such as a named sub-logger whose level can be changed
+// apart from the scheduler's
+func WithJobLogger(logger Logger) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.logger = logger
+	}
+}
+
+// WithGracePeriod bounds how long a running job may keep going once a
+// draining shutdown begins; its context is cancelled when the period ends.
+// Jobs without one run until the shutdown deadline.
+func WithGracePeriod(grace time.Duration) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.grace = grace
+	}
+}
+
+// WithRunOnStart runs the job once as soon as Start is called, through the
+// same checks and wrapping as a scheduled fire, instead of waiting for the
+// first tick. It has no effect on jobs added to a running scheduler.
+func WithRunOnStart() JobOption {
+	return func(cfg *jobConfig) {
+		cfg.runOnStart = true
+	}
+}
+
 // AddJob adds a new job with enhanced wrapping
-func (ec *EnhancedCron) AddJob(spec string, job cron.Job, name string) (cron.EntryID, error) {
-	wrappedJob := ec.wrapJob(job, name)
-	return ec.cron.AddJob(spec, wrappedJob)
+func (ec *EnhancedCron) AddJob(spec string, job cron.Job, name string, opts ...JobOption) (cron.EntryID, error) {
+	return ec.registerJob(spec, nil, job, name, false, opts)
 }
 
-// In the wrapJob function, modify the job execution:
-func (ec *EnhancedCron) wrapJob(job cron.Job, name string) cron.Job {
-	return cron.FuncJob(func() {
-		// Create job-specific context with timeout
-		jobCtx, cancel := context.WithTimeout(ec.shutdownCtx, ec.timeout)
-		defer cancel()
+// AddScheduledJob adds a job driven by a custom Schedule implementation, with
+// the same wrapping, metadata and shutdown handling as AddJob
+func (ec *EnhancedCron) AddScheduledJob(schedule cron.Schedule, job cron.Job, name string, opts ...JobOption) (cron.EntryID, error) {
+	if schedule == nil {
+		return 0, fmt.Errorf("job %q has no schedule", name)
+	}
+	return ec.registerJob(describeSchedule(schedule), schedule, job, name, false, opts)
+}
 
-		metadata := &JobMetadata{
-			Name:      name,
-			StartTime: time.Now(),
-			Status:    StatusRunning,
+// UpdateJob replaces the schedule, body and options of an existing job.
+// Runs already in flight finish undisturbed and still count toward the
+// job's overlap limits; history carries over to the updated job.
+func (ec *EnhancedCron) UpdateJob(spec string, job cron.Job, name string, opts ...JobOption) (cron.EntryID, error) {
+	return ec.registerJob(spec, nil, job, name, true, opts)
+}
+
+// registerJob adds a job, or replaces an existing one when update is set.
+// The spec is parsed unless a schedule is given.
+func (ec *EnhancedCron) registerJob(spec string, schedule cron.Schedule, job cron.Job, name string, update bool, opts []JobOption) (id cron.EntryID, err error) {
+	cfg := &jobConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if err := cfg.parseParams(); err != nil {
+		return 0, fmt.Errorf("job %q: %w", name, err)
+	}
+	if cfg.idempotencyKey != nil && ec.store == nil {
+		return 0, fmt.Errorf("job %q: idempotency keys need a store", name)
+	}
+
+	entry := &jobEntry{name: name, spec: spec, cfg: cfg, job: job, jobState: &jobState{}}
+	if cfg.breakerThreshold > 0 {
+		entry.breaker = newCircuitBreaker(cfg.breakerThreshold, cfg.breakerCooldown)
+	}
+
+	if !cfg.endAt.IsZero() && !ec.clock.Now().Before(cfg.endAt) {
+		return 0, fmt.Errorf("job %q expired at %s", name, cfg.endAt.Format(time.RFC3339))
+	}
+	deadline, err := parseSLA(name, cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	// Deferred first so it runs after the unlock
+	defer func() {
+		if err != nil {
+			return
 		}
+		action := AuditRegistered
+		if update {
+			action = AuditUpdated
+		}
+		ec.recordChange(action, name, cfg.auditActor(), spec, cfg)
+	}()
 
-		// Create a WaitGroup for this specific job
-		var wg sync.WaitGroup
-		wg.Add(1)
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
 
-		// Store active job with the WaitGroup
-		jobInfo := struct {
-			metadata *JobMetadata
-			wg       *sync.WaitGroup
-		}{metadata, &wg}
-
-		ec.activeJobs.Store(name, jobInfo)
-		defer ec.activeJobs.Delete(name)
-
-		// Run job in goroutine
-		go func() {
-			defer wg.Done() // Signal completion
-			defer func() {
-				if r := recover(); r != nil {
-					metadata.Status = StatusFailed
-					metadata.Error = fmt.Errorf("job panic: %v", r)
-					// Log panic
-				}
-			}()
-
-			job.Run()
-			metadata.Status = StatusCompleted
-		}()
-
-		// Wait for either job completion or context cancellation
-		select {
-		case <-jobCtx.Done():
-			// Wait for job to actually finish even after cancellation
-			wg.Wait()
-			metadata.Status = StatusCancelled
-			metadata.Error = jobCtx.Err()
-		case <-waitWithTimeout(&wg, ec.timeout):
-			// Job completed normally
+	old, exists := ec.jobs[name]
+	switch {
+	case exists && !update:
+		return 0, fmt.Errorf("job %q already exists", name)
+	case !exists && update:
+		return 0, fmt.Errorf("job %q not found", name)
+	case exists:
+		entry.jobState = old.jobState
+		if old.breaker != nil && old.cfg.breakerThreshold == cfg.breakerThreshold && old.cfg.breakerCooldown == cfg.breakerCooldown {
+			entry.breaker = old.breaker
 		}
+	}
 
-		metadata.EndTime = time.Now()
-	})
+	if schedule == nil {
+		var err error
+		if schedule, err = parseSchedule(spec, cfg.location); err != nil {
+			return 0, err
+		}
+	} else {
+		schedule = inLocation(schedule, cfg.location)
+	}
+	entry.schedule = schedule
+	entry.location = scheduleLocation(schedule)
+
+	wrappedJob := ec.wrapJob(job, entry)
+	entry.run = wrappedJob
+	effective := schedule
+	if cfg.backoffMax > 0 {
+		effective = backoffSchedule{schedule, cfg.backoffMax, &entry.backoffLevel}
+	}
+	if cfg.tightRatio > 0 && cfg.tightPolicy == TightDelay {
+		effective = paceSchedule{effective, &entry.paceGap}
+	}
+	id = ec.sched.Schedule(untilSchedule{effective, cfg.endAt}, wrappedJob)
+	if exists {
+		ec.unschedule(old)
+	}
+	ec.scheduleExpiry(entry)
+	ec.scheduleSLA(entry, deadline)
+
+	entry.id = id
+	ec.jobs[name] = entry
+	return id, nil
+}
+
+// RemoveJob unregisters a job so it no longer fires. Runs already in flight
+// finish normally.
+func (ec *EnhancedCron) RemoveJob(name string) error {
+	return ec.removeJob(name, ActorAPI)
+}
+
+// TriggerJob runs the job once now, in the background, through the same
+// checks and wrapping as a scheduled fire
+func (ec *EnhancedCron) TriggerJob(name string) error {
+	return ec.triggerJob(name, TriggerManual)
+}
+
+func (ec *EnhancedCron) triggerJob(name, trigger string) error {
+	if err := ec.writable(); err != nil {
+		return err
+	}
+	ec.mu.RLock()
+	entry, ok := ec.jobs[name]
+	ec.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("job %q not found", name)
+	}
+	go ec.trigger(entry.job, entry, trigger)
+	return nil
+}
+
+// removeJob unregisters a job on behalf of actor
+func (ec *EnhancedCron) removeJob(name, actor string) error {
+	ec.mu.Lock()
+	entry, ok := ec.jobs[name]
+	if ok {
+		ec.unschedule(entry)
+		delete(ec.jobs, name)
+	}
+	ec.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("job %q not found", name)
+	}
+	ec.recordChange(AuditRemoved, name, actor, "", nil)
+	return nil
+}
+
+// wrapJob returns the job the scheduler fires on the job's schedule
+func (ec *EnhancedCron) wrapJob(job cron.Job, entry *jobEntry) cron.Job {
+	return scheduledJob{ec: ec, job: job, entry: entry}
+}
+
+// scheduledJob fires a registered job on its schedule
+type scheduledJob struct {
+	ec    *EnhancedCron
+	job   cron.Job
+	entry *jobEntry
+}
+
+// Run fires the job as due now
+func (j scheduledJob) Run() {
+	j.ec.triggerAt(j.job, j.entry, TriggerSchedule, j.ec.clock.Now())
+}
+
+// runDue fires the job for the slot that was due at due
+func (j scheduledJob) runDue(due time.Time) {
+	j.ec.triggerAt(j.job, j.entry, TriggerSchedule, due)
+}
+
+// trigger fires the job now on behalf of trigger
+func (ec *EnhancedCron) trigger(job cron.Job, entry *jobEntry, trigger string) {
+	ec.triggerAt(job, entry, trigger, ec.clock.Now())
+}
+
+// triggerAt fires the job on behalf of trigger for the slot due at due,
+// which is the fire's scheduled time; a zero due means now
+func (ec *EnhancedCron) triggerAt(job cron.Job, entry *jobEntry, trigger string, due time.Time) {
+	if due.IsZero() {
+		due = ec.clock.Now()
+	}
+	ec.triggerFiring(job, entry, firing{scheduled: due, trigger: trigger})
+}
+
+// triggerFiring takes a fire through the pause and calendar checks
+func (ec *EnhancedCron) triggerFiring(job cron.Job, entry *jobEntry, f firing) {
+	ec.debug("fire", "job", entry.name, "trigger", f.trigger, "due", f.scheduled, "next", entry.next(ec.clock.Now()))
+	if !manualTrigger(f.trigger) && entry.isPaused() {
+		ec.emitSkipped(entry, "job paused")
+		return
+	}
+	ec.recordTrigger(entry, f)
+	if !ec.checkCalendar(job, entry, f) {
+		return
+	}
+	ec.fire(job, entry, f)
+}
+
+// fire takes a single fire of the job through its admission checks and
+// hands it to the executor
+func (ec *EnhancedCron) fire(job cron.Job, entry *jobEntry, f firing) {
+	// Windows are judged against the fire time, before any splay
+	if ok, reason := entry.checkWindows(ec.clock.Now()); !ok {
+		ec.emitSkipped(entry, reason)
+		return
+	}
+
+	if ec.dryRun {
+		ec.logger.Info("dry run: job %s would run at %s", entry.name, ec.clock.Now().Format(time.RFC3339))
+		ec.emitSkipped(entry, "dry run")
+		return
+	}
+
+	// Spread the fire out before taking any slots
+	if !ec.applyJitter(entry, f) {
+		return
+	}
+
+	// Keep a tight schedule from piling runs up
+	if !ec.checkPace(entry) {
+		return
+	}
+
+	// Apply the overlap policy before anything else touches the job state
+	if !ec.acquireOverlap(entry, f) {
+		return
+	}
+	ec.submit(job, entry, f)
+}
+
+// execute runs a fire that already holds an overlap slot
+func (ec *EnhancedCron) execute(job cron.Job, entry *jobEntry, f firing) {
+	name := entry.name
+	defer ec.releaseOverlap(job, entry)
+
+	// Fires that were waiting in a queue when shutdown began are dropped
+	if ec.stoppingContext().Err() != nil {
+		ec.emitSkipped(entry, "scheduler shutting down")
+		return
+	}
+	if ec.isRetired(entry) {
+		ec.emitSkipped(entry, errNamespaceShutdown.Error())
+		return
+	}
+
+	// Wait for a run slot of the job's namespace
+	release, ok := ec.acquireNamespace(entry)
+	if !ok {
+		return
+	}
+	defer release()
+
+	// Fires that stop short of running give back the breaker probe, the
+	// idempotency key, the rate limit token and the run they hold
+	var probe, token, claimed bool
+	var idempotencyKey string
+	started := false
+	defer func() {
+		if !started {
+			ec.releaseProbe(entry, probe)
+			ec.completeIdempotency(idempotencyKey, nil)
+			ec.refundRateLimit(token)
+			ec.releaseRun(entry, claimed)
+		}
+	}()
+
+	// Skip the fire while the job's circuit breaker is open
+	if probe, ok = ec.allowByBreaker(entry); !ok {
+		return
+	}
+
+	// Skip the fire once the job has used its execution budget
+	if !ec.checkBudget(entry) {
+		return
+	}
+
+	timeout := ec.timeout
+	if entry.cfg.timeout > 0 {
+		timeout = entry.cfg.timeout
+	}
+
+	// Skip the fire if its idempotency key is reserved or completed
+	if idempotencyKey, ok = ec.checkIdempotency(entry, f); !ok {
+		return
+	}
+
+	// Skip the fire if one of the job's preconditions does not hold
+	if !ec.checkPreconditions(entry, timeout) {
+		return
+	}
+
+	// Skip the fire if its input is unchanged since the last success
+	fingerprint, ok := ec.checkFingerprint(entry, timeout)
+	if !ok {
+		return
+	}
+
+	// Wait for the shared rate limiter to admit the run, once nothing is
+	// left to skip it
+	if token, ok = ec.waitForRateLimit(entry); !ok {
+		return
+	}
+
+	// Create job-specific context with timeout
+	runCtx, cancelRun := context.WithCancelCause(ec.shutdownContext())
+	defer cancelRun(nil)
+	jobCtx, cancel := ec.runContext(runCtx, entry, timeout)
+	defer cancel()
+
+	metadata := &JobMetadata{
+		ID:            entry.id,
+		RunID:         newID(),
+		Name:          name,
+		ScheduledTime: f.scheduled,
+		StartTime:     ec.clock.Now(),
+		Status:        StatusRunning,
+		Trigger:       f.trigger,
+		Fingerprint:   fingerprint,
+	}
+	params, err := entry.cfg.expandParams(metadata)
+	if err != nil {
+		ec.logger.Error("skipping job %s: %v", name, err)
+		ec.emitSkipped(entry, err.Error())
+		return
+	}
+	if f.params != nil {
+		params = f.params
+	}
+	metadata.Params = params
+	jobCtx = withRunIdentity(jobCtx, metadata)
+
+	// Count the run against the job's run limit
+	var last bool
+	if claimed, last = ec.claimRun(entry); !claimed {
+		return
+	}
+
+	// Journal the run before it starts so a crash leaves a trace. The run
+	// is given back before the skip is reported, so the next fire can have it.
+	if !ec.journalRun(entry, metadata) {
+		ec.releaseRun(entry, claimed)
+		claimed = false
+		ec.emitSkipped(entry, "run journal unavailable")
+		return
+	}
+
+	// Create a WaitGroup for this specific job
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	status := &runStatus{}
+	status.publish(metadata)
+	reporter := &progressReporter{ec: ec, entry: entry, status: status}
+	jobCtx = context.WithValue(jobCtx, reporterKey, Reporter(reporter))
+
+	// Store active job with the WaitGroup
+	jobInfo := activeJob{metadata: metadata, status: status, wg: &wg, cancel: cancelRun, grace: entry.cfg.grace, progress: reporter}
+
+	// Active runs are keyed by run ID so overlapping runs of the same
+	// job don't overwrite each other
+	if !ec.admitRun(entry, jobInfo) {
+		ec.completeJournal(metadata.RunID)
+		ec.releaseRun(entry, claimed)
+		claimed = false
+		ec.emitSkipped(entry, errNamespaceShutdown.Error())
+		return
+	}
+	defer ec.activeJobs.Delete(metadata.RunID)
+	started = true
+	if f.accepted != nil {
+		f.accepted()
+	}
+	if last {
+		ec.deregister(entry)
+		defer ec.emitExpired(entry, maxRunsReason(entry.cfg.maxRuns))
+	}
+
+	ec.emit(EventJobStarted, entry, metadata)
+
+	var usage *usageTracker
+	if ec.trackResources {
+		usage = startUsage()
+	}
+
+	// Run job in goroutine; it keeps its own metadata if it is orphaned
+	go func(metadata *JobMetadata) {
+		defer wg.Done() // Signal completion
+		if err := ec.runWithRetry(jobCtx, job, entry, metadata, status); err != nil {
+			metadata.Status = StatusFailed
+			metadata.Error = err
+			ec.logger.Error("job %s failed after %d attempt(s): %v", name, metadata.Attempt, err)
+			return
+		}
+		metadata.Status = StatusCompleted
+		metadata.Error = nil
+	}(metadata)
+
+	// Wait for either job completion or context cancellation
+	select {
+	case <-jobCtx.Done():
+		// Wait for job to actually finish even after cancellation, unless
+		// it is hung; an orphaned run keeps its own metadata
+		if stacks, hung := ec.awaitCancelled(&wg, metadata); hung {
+			metadata = orphanedRun(metadata, stacks)
+		}
+		metadata.Status = StatusCancelled
+		metadata.Error = context.Cause(jobCtx)
+	case <-waitWithTimeout(&wg, timeout):
+		// Job completed normally
+	}
+
+	metadata.EndTime = ec.clock.Now()
+	metadata.Progress = reporter.load()
+	finishUsage(usage, metadata)
+	ec.chargeBudget(entry, metadata)
+	ec.recordSuccess(entry, metadata)
+	ec.recordHistory(entry, metadata)
+	ec.recordPace(entry, metadata)
+	ec.emit(eventForStatus(metadata.Status), entry, metadata)
+	ec.recordBreaker(entry, metadata)
+	ec.recordFailureStreak(entry, metadata)
+	ec.deadLetter(entry, metadata)
+	ec.completeIdempotency(idempotencyKey, metadata)
+	ec.recordFingerprint(entry, metadata)
+	ec.finalize(entry, *metadata, timeout)
+	if metadata.Orphaned {
+		ec.trackOrphan(&wg, metadata)
+	} else {
+		ec.completeJournal(metadata.RunID)
+	}
 }
 
 // Helper function to wait with timeout
@@ -153,32 +839,143 @@ func waitWithTimeout(wg *sync.WaitGroup, timeout time.Duration) <-chan struct{}
 	return ch
 }
 
-// Start starts the better_cron scheduler
+var idSeq uint64
+
+// newID returns an identifier that is unique within the process and
+// sortable by creation time
+func newID() string {
+	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), atomic.AddUint64(&idSeq, 1))
+}
+
+// Start starts the better_cron scheduler. A scheduler that was shut down
+// can be started again: registered jobs are kept and new runs get fresh
+// contexts. File watchers and signal handlers stop at shutdown and must be
+// set up again.
 func (ec *EnhancedCron) Start() {
-	ec.cron.Start()
+	ec.lifeMu.Lock()
+	defer ec.lifeMu.Unlock()
+
+	if ec.stoppingContext().Err() != nil {
+		ec.life.Store(newLifecycle())
+		if ec.pool != nil {
+			ec.pool.start(ec.stoppingContext())
+		}
+	}
+	if !ec.sched.Start() {
+		return
+	}
+	if ec.journal && !ec.readOnly {
+		ec.recoverOnce.Do(ec.recoverJournal)
+	}
+
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	now := ec.clock.Now()
+	for _, entry := range ec.jobs {
+		ec.debug("next run", "job", entry.name, "next", entry.next(now))
+		if entry.cfg.runOnStart {
+			ec.sched.Run(cron.FuncJob(func() { ec.trigger(entry.job, entry, TriggerStart) }))
+		}
+	}
+}
+
+// lifecycle holds the contexts of one Start/Shutdown cycle. stopping ends
+// when shutdown begins and stops new work; shutdown is cancelled to abort
+// the jobs that are still running.
+type lifecycle struct {
+	shutdown       context.Context
+	cancelShutdown context.CancelFunc
+	stopping       context.Context
+	cancelStopping context.CancelFunc
 }
 
-// Then modify the Shutdown method:
-func (ec *EnhancedCron) Shutdown() error {
-	// Signal shutdown to all jobs
-	ec.cancelShutdown()
+func newLifecycle() *lifecycle {
+	shutdown, cancelShutdown := context.WithCancel(context.Background())
+	stopping, cancelStopping := context.WithCancel(shutdown)
+	return &lifecycle{shutdown, cancelShutdown, stopping, cancelStopping}
+}
 
-	// Create timeout context for shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), ec.timeout)
-	defer cancel()
+// shutdownContext is cancelled when running jobs must stop
+func (ec *EnhancedCron) shutdownContext() context.Context {
+	return ec.life.Load().shutdown
+}
+
+// stoppingContext is cancelled as soon as shutdown begins
+func (ec *EnhancedCron) stoppingContext() context.Context {
+	return ec.life.Load().stopping
+}
+
+// ShutdownMode selects how Shutdown treats runs that are still executing
+type ShutdownMode int
+
+const (
+	// ShutdownDrain lets running jobs finish, cancelling them only if the
+	// shutdown context expires first
+	ShutdownDrain ShutdownMode = iota
+	// ShutdownAbort cancels the contexts of running jobs at once and waits
+	// at most abortWait for them to return
+	ShutdownAbort
+)
+
+func (m ShutdownMode) String() string {
+	if m == ShutdownAbort {
+		return "abort"
+	}
+	return "drain"
+}
+
+// abortWait bounds how long an aborting shutdown waits for cancelled jobs
+const abortWait = 5 * time.Second
+
+// Shutdown stops scheduling new fires, drops queued ones and then handles
+// running jobs according to mode. If ctx has no deadline, the WithTimeout
+// duration is used. An error is returned if jobs were still running when
+// the deadline passed.
+func (ec *EnhancedCron) Shutdown(ctx context.Context, mode ShutdownMode) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ec.timeout)
+		defer cancel()
+	}
+	ec.lifeMu.Lock()
+	defer ec.lifeMu.Unlock()
+	life := ec.life.Load()
 
-	// Stop accepting new jobs
-	stopCtx := ec.cron.Stop()
+	// Whatever happens, running jobs are cancelled once Shutdown returns
+	defer life.cancelShutdown()
+
+	// Stop accepting new fires
+	ec.debug("shutdown: stopped accepting fires", "mode", mode)
+	life.cancelStopping()
+	if mode == ShutdownAbort {
+		life.cancelShutdown()
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, abortWait)
+		defer cancel()
+	}
+	stopCtx := ec.sched.Stop()
 
 	// Create a WaitGroup for all jobs
 	var wg sync.WaitGroup
+	running := 0
 
-	// Wait for all jobs to actually complete
+	// Wait for all jobs to actually complete, cutting off those whose grace
+	// period runs out first
+	var graceTimers []*time.Timer
+	defer func() {
+		for _, timer := range graceTimers {
+			timer.Stop()
+		}
+	}()
 	ec.activeJobs.Range(func(key, value interface{}) bool {
-		jobInfo := value.(struct {
-			metadata *JobMetadata
-			wg       *sync.WaitGroup
-		})
+		jobInfo := value.(activeJob)
+		if mode == ShutdownDrain && jobInfo.grace > 0 {
+			graceTimers = append(graceTimers, time.AfterFunc(jobInfo.grace, func() {
+				ec.debug("shutdown: grace period expired", "job", jobInfo.metadata.Name, "run_id", jobInfo.metadata.RunID)
+				jobInfo.cancel(errGracePeriodExpired)
+			}))
+		}
+		running++
 		wg.Add(1)
 		go func(jobWg *sync.WaitGroup) {
 			defer wg.Done()
@@ -187,6 +984,7 @@ func (ec *EnhancedCron) Shutdown() error {
 		}(jobInfo.wg)
 		return true
 	})
+	ec.debug("shutdown: waiting for running jobs", "running", running)
 
 	// Wait for all components
 	done := make(chan struct{})
@@ -196,32 +994,106 @@ func (ec *EnhancedCron) Shutdown() error {
 		<-stopCtx.Done() // Wait for cron to stop
 	}()
 
-	// Wait for shutdown completion or timeout
-	select {
-	case <-shutdownCtx.Done():
-		return fmt.Errorf("shutdown timed out after %v", ec.timeout)
-	case <-done:
-		return nil
+	// Wait for shutdown completion or timeout, reporting who is holding it up
+	started := time.Now()
+	deadline, _ := ctx.Deadline()
+	ticker := time.NewTicker(ec.progressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			progress := ec.shutdownProgress(started, deadline)
+			ec.debug("shutdown: timed out", "elapsed", time.Since(started))
+			return fmt.Errorf("shutdown did not complete: %w; still running: %s", ctx.Err(), progress.describeRunning())
+		case <-done:
+			ec.debug("shutdown: complete", "elapsed", time.Since(started))
+			return nil
+		case <-ticker.C:
+			ec.reportShutdown(ec.shutdownProgress(started, deadline))
+		}
 	}
 }
 
-// GetJobStatus returns the current status of a job by name
+// GetJobStatus returns a snapshot of the current run of a job by name. If
+// several runs of the job overlap, the earliest started one is returned.
 func (ec *EnhancedCron) GetJobStatus(name string) (*JobMetadata, bool) {
-	if value, ok := ec.activeJobs.Load(name); ok {
-		return value.(*JobMetadata), true
+	var found *activeJob
+	ec.activeJobs.Range(func(key, value interface{}) bool {
+		job := value.(activeJob)
+		if job.metadata.Name == name && (found == nil || job.metadata.StartTime.Before(found.metadata.StartTime)) {
+			found = &job
+		}
+		return true
+	})
+	if found == nil {
+		return nil, false
 	}
-	return nil, false
+	return found.snapshot(), true
 }
 
-// GetActiveJobs returns a list of all currently running jobs
+// GetActiveJobs returns snapshots of all currently running jobs, with the
+// progress each last reported
 func (ec *EnhancedCron) GetActiveJobs() []*JobMetadata {
 	var jobs []*JobMetadata
 	ec.activeJobs.Range(func(key, value interface{}) bool {
-		metadata := value.(*JobMetadata)
-		if metadata.Status == StatusRunning {
-			jobs = append(jobs, metadata)
+		job := value.(activeJob)
+		if job.status.load().Status == StatusRunning {
+			jobs = append(jobs, job.snapshot())
 		}
 		return true
 	})
 	return jobs
 }
+
+// JobInfo describes a registered job and its schedule
+type JobInfo struct {
+	ID       cron.EntryID
+	Name     string
+	Spec     string
+	Tags     []string
+	Timezone *time.Location
+	Next     time.Time
+	Prev     time.Time
+
+	// RunOnStart is set for jobs that also run when the scheduler starts
+	RunOnStart bool
+	// EndAt is when the job expires, or the zero time if it never does
+	EndAt time.Time
+	// Namespace is the part of Name before the first "/", if any
+	Namespace string
+	// Paused is set while the job is paused, see PauseJob
+	Paused bool
+	// Description is the schedule in plain English, see DescribeSpec, or
+	// empty for custom schedules
+	Description string
+}
+
+// ListJobs returns every registered job sorted by name
+func (ec *EnhancedCron) ListJobs() []JobInfo {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+
+	jobs := make([]JobInfo, 0, len(ec.jobs))
+	for _, entry := range ec.jobs {
+		scheduled := ec.sched.Entry(entry.id)
+		namespace, _ := SplitName(entry.name)
+		jobs = append(jobs, JobInfo{
+			ID:       entry.id,
+			Name:     entry.name,
+			Spec:     entry.spec,
+			Tags:     entry.cfg.tags,
+			Timezone: entry.location,
+			Next:     scheduled.Next,
+			Prev:     scheduled.Prev,
+
+			RunOnStart: entry.cfg.runOnStart,
+			EndAt:      entry.cfg.endAt,
+			Namespace:  namespace,
+			Paused:     entry.isPaused(),
+
+			Description: describeJob(entry.spec),
+		})
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
@@ -0,0 +1,44 @@
+package better_cron
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronLogger adapts a Logger to robfig's cron.Logger
+type cronLogger struct {
+	logger Logger
+}
+
+// NewCronLogger adapts logger to robfig's cron.Logger, for use with robfig
+// job wrappers such as cron.Recover. Key-value pairs are appended to the
+// message as key=value.
+func NewCronLogger(logger Logger) cron.Logger {
+	return cronLogger{logger: logger}
+}
+
+// Info logs routine messages
+func (l cronLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Info("%s", formatKeysAndValues(msg, keysAndValues))
+}
+
+// Error logs an error condition
+func (l cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.logger.Error("%s", formatKeysAndValues(msg, append(keysAndValues, "error", err)))
+}
+
+// formatKeysAndValues appends alternating keys and values to msg
+func formatKeysAndValues(msg string, keysAndValues []interface{}) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 < len(keysAndValues) {
+			fmt.Fprintf(&b, " %v=%v", keysAndValues[i], keysAndValues[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", keysAndValues[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package better_cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const deadLetterPrefix = "deadletter/"
+
+// DeadLetter is a run that failed after exhausting its retries
+type DeadLetter struct {
+	ID             string    `json:"id"`
+	Job            string    `json:"job"`
+	Attempts       int       `json:"attempts"`
+	Error          string    `json:"error"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	DeadLetteredAt time.Time `json:"dead_lettered_at"`
+	// Params are the run's expanded parameters, see WithParams
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// deadLetter stores a failed run of a job with a retry policy in the
+// configured store
+func (ec *EnhancedCron) deadLetter(entry *jobEntry, metadata *JobMetadata) {
+	if ec.store == nil || entry.cfg.maxAttempts == 0 || metadata.Status != StatusFailed {
+		return
+	}
+
+	now := ec.clock.Now()
+	letter := DeadLetter{
+		ID:             newID(),
+		Job:            entry.name,
+		Attempts:       metadata.Attempt,
+		StartTime:      metadata.StartTime,
+		EndTime:        metadata.EndTime,
+		DeadLetteredAt: now,
+		Params:         metadata.Params,
+	}
+	if metadata.Error != nil {
+		letter.Error = metadata.Error.Error()
+	}
+
+	data, err := json.Marshal(letter)
+	if err == nil {
+		err = ec.store.Put(deadLetterPrefix+letter.ID, data)
+	}
+	if err != nil {
+		ec.logger.Error("failed to dead-letter run of job %s: %v", entry.name, err)
+		return
+	}
+
+	ec.logger.Error("job %s exhausted %d attempts, dead-lettered as %s", entry.name, metadata.Attempt, letter.ID)
+	ec.emit(EventJobDeadLettered, entry, metadata)
+}
+
+// ListDeadLetters returns the dead-lettered runs of a job, oldest first.
+// An empty name lists the runs of all jobs.
+func (ec *EnhancedCron) ListDeadLetters(name string) ([]DeadLetter, error) {
+	if ec.store == nil {
+		return nil, fmt.Errorf("no store configured")
+	}
+
+	keys, err := ec.store.List(deadLetterPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var letters []DeadLetter
+	for _, key := range keys {
+		letter, ok, err := ec.loadDeadLetter(strings.TrimPrefix(key, deadLetterPrefix))
+		if err != nil {
+			return nil, err
+		}
+		if ok && (name == "" || letter.Job == name) {
+			letters = append(letters, letter)
+		}
+	}
+	return letters, nil
+}
+
+// RedriveDeadLetter runs the job of a dead-lettered run again in the
+// background through the full job pipeline, with the params the run had.
+// The run leaves the dead-letter queue once the re-driven run starts; if
+// the fire is dropped, e.g. because the job is paused or the scheduler is
+// shutting down, it stays queued.
+func (ec *EnhancedCron) RedriveDeadLetter(id string) error {
+	if err := ec.writable(); err != nil {
+		return err
+	}
+	if ec.store == nil {
+		return fmt.Errorf("no store configured")
+	}
+
+	letter, ok, err := ec.loadDeadLetter(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("dead letter %q not found", id)
+	}
+
+	ec.mu.RLock()
+	entry, exists := ec.jobs[letter.Job]
+	ec.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("job %q no longer exists", letter.Job)
+	}
+
+	ec.logger.Info("re-driving dead-lettered run %s of job %s", id, letter.Job)
+	f := firing{scheduled: ec.clock.Now(), trigger: TriggerRedrive, params: letter.Params}
+	f.accepted = func() {
+		if err := ec.store.Delete(deadLetterPrefix + id); err != nil {
+			ec.logger.Error("failed to remove re-driven dead letter %s: %v", id, err)
+		}
+	}
+	go ec.triggerFiring(entry.job, entry, f)
+	return nil
+}
+
+// PurgeDeadLetters deletes the dead-lettered runs of a job and returns how
+// many were removed. An empty name purges the runs of all jobs.
+func (ec *EnhancedCron) PurgeDeadLetters(name string) (int, error) {
+	if err := ec.writable(); err != nil {
+		return 0, err
+	}
+	letters, err := ec.ListDeadLetters(name)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, letter := range letters {
+		if err := ec.store.Delete(deadLetterPrefix + letter.ID); err != nil {
+			return i, err
+		}
+	}
+	return len(letters), nil
+}
+
+func (ec *EnhancedCron) loadDeadLetter(id string) (DeadLetter, bool, error) {
+	var letter DeadLetter
+	data, ok, err := ec.store.Get(deadLetterPrefix + id)
+	if err != nil || !ok {
+		return letter, ok, err
+	}
+	if err := json.Unmarshal(data, &letter); err != nil {
+		return letter, false, fmt.Errorf("decode dead letter %s: %w", id, err)
+	}
+	return letter, true, nil
+}
@@ -0,0 +1,16 @@
+package better_cron
+
+// DebugLogger is implemented by loggers with a debug level, such as
+// custom_logger.Logger. Scheduler decisions (next runs, skipped fires, queue
+// depth and shutdown phases) are logged at that level when the logger given
+// to WithLogger has one.
+type DebugLogger interface {
+	Debug(msg string, args ...interface{})
+}
+
+// debug logs a scheduler decision with alternating keys and values
+func (ec *EnhancedCron) debug(msg string, keysAndValues ...interface{}) {
+	if dl, ok := ec.logger.(DebugLogger); ok {
+		dl.Debug("%s", formatKeysAndValues(msg, keysAndValues))
+	}
+}
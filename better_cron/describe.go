@@ -0,0 +1,261 @@
+package better_cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// specDescriptors maps the fixed descriptors to the spec they stand for
+var specDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// DescribeSpec describes a spec accepted by AddJob in plain English, e.g.
+// "At 09:30 on Monday through Friday" for "0 30 9 * * 1-5", so schedules
+// can be reviewed without parsing cron syntax. Natural language, ISO 8601,
+// random, sun and Quartz day specs are returned as written.
+func DescribeSpec(spec string) (string, error) {
+	if _, err := ValidateSpec(spec); err != nil {
+		return "", err
+	}
+	prefix, body := splitTimezone(spec)
+
+	var text string
+	fields := strings.Fields(body)
+	switch {
+	case strings.HasPrefix(body, "@every "):
+		text = "Every " + strings.TrimSpace(strings.TrimPrefix(body, "@every "))
+	case specDescriptors[body] != "":
+		text = describeFields(strings.Fields(specDescriptors[body]))
+	case len(fields) == len(specFields) && !strings.HasPrefix(body, "@") && !isNaturalSpec(body) &&
+		!isISOInterval(body) && !isRandomSpec(body) && !isSunSpec(body) && !hasQuartzDays(fields):
+		text = describeFields(fields)
+	default:
+		text = body
+	}
+
+	if prefix != "" {
+		text += " (" + strings.TrimSpace(prefix[strings.IndexByte(prefix, '=')+1:]) + ")"
+	}
+	return text, nil
+}
+
+// describeFields describes a valid six-field spec
+func describeFields(fields []string) string {
+	sets := make([]fieldSet, len(specFields))
+	for i, f := range specFields {
+		set, _, _ := f.check(fields[i])
+		sets[i] = newFieldSet(f, set)
+	}
+	return describeTime(sets[0], sets[1], sets[2]) +
+		describeDays(sets[3], sets[4], sets[5], isWildcard(fields[3]), isWildcard(fields[5]))
+}
+
+// describeTime describes when in a day a spec fires
+func describeTime(secs, mins, hours fieldSet) string {
+	sec, secOne := secs.single()
+	min, minOne := mins.single()
+
+	if secOne && minOne && !hours.all() && hours.every() == 0 && len(hours.values) <= 4 {
+		clocks := make([]string, len(hours.values))
+		for i, h := range hours.values {
+			clocks[i] = clockText(h, min, sec)
+		}
+		return "At " + joinWords(clocks)
+	}
+
+	var lead string
+	switch {
+	case secOne && minOne:
+		lead = "At minute " + fmt.Sprint(min)
+		if sec != 0 {
+			lead += fmt.Sprintf(" and second %d", sec)
+		}
+		switch n := hours.every(); {
+		case hours.all() && min == 0 && sec == 0:
+			return "Every hour"
+		case hours.all():
+			return lead + " of every hour"
+		case n > 0 && min == 0 && sec == 0:
+			return fmt.Sprintf("Every %d hours", n)
+		case n > 0:
+			return fmt.Sprintf("%s of every %d hours", lead, n)
+		case min == 0 && sec == 0:
+			lead = "Every hour"
+		}
+	case secOne:
+		lead = repeatText(mins, "minute", "At minutes")
+		if sec != 0 {
+			lead += fmt.Sprintf(" at second %d", sec)
+		}
+		if hours.all() && mins.every() == 0 && !mins.all() {
+			lead += " of every hour"
+		}
+	default:
+		lead = repeatText(secs, "second", "At seconds")
+		if !mins.all() {
+			if n := mins.every(); n > 0 {
+				lead += fmt.Sprintf(" of every %d minutes", n)
+			} else {
+				lead += " during " + plural(mins, "minute") + " " + mins.text(nil)
+			}
+		}
+	}
+
+	switch n := hours.every(); {
+	case hours.all():
+		return lead
+	case n > 0:
+		return fmt.Sprintf("%s of every %d hours", lead, n)
+	case hours.values[len(hours.values)-1]-hours.values[0] == len(hours.values)-1:
+		first, last := hours.values[0], hours.values[len(hours.values)-1]
+		return fmt.Sprintf("%s between %s and %s", lead, clockText(first, 0, 0), clockText(last, 59, 0))
+	}
+	return lead + " during " + plural(hours, "hour") + " " + hours.text(nil)
+}
+
+// describeDays describes on which days a spec fires, empty for every day
+func describeDays(doms, months, dows fieldSet, domAny, dowAny bool) string {
+	var text string
+	domText := " on " + plural(doms, "day") + " " + doms.text(nil) + " of the month"
+	if n := doms.every(); n > 0 {
+		domText = fmt.Sprintf(" every %d days", n)
+	}
+	dowText := " on " + dows.text(func(d int) string { return time.Weekday(d).String() })
+
+	switch {
+	case domAny && dowAny:
+	case domAny:
+		text = dowText
+	case dowAny:
+		text = domText
+	default:
+		// cron fires on days matching either field
+		text = domText + " or" + dowText
+	}
+	if !months.all() {
+		text += " in " + months.text(func(m int) string { return time.Month(m).String() })
+	}
+	return text
+}
+
+// repeatText describes a field repeating within the next larger unit
+func repeatText(f fieldSet, unit, list string) string {
+	if f.all() {
+		return "Every " + unit
+	}
+	if n := f.every(); n > 0 {
+		return fmt.Sprintf("Every %d %ss", n, unit)
+	}
+	if len(f.values) == 1 {
+		list = strings.TrimSuffix(list, "s")
+	}
+	return list + " " + f.text(nil)
+}
+
+// fieldSet is the values a spec field matches
+type fieldSet struct {
+	values   []int
+	min, max int
+}
+
+func newFieldSet(f specField, set []bool) fieldSet {
+	fs := fieldSet{min: f.min, max: f.max}
+	for v := f.min; v <= f.max; v++ {
+		if set[v] {
+			fs.values = append(fs.values, v)
+		}
+	}
+	return fs
+}
+
+func (f fieldSet) all() bool {
+	return len(f.values) == f.max-f.min+1
+}
+
+func (f fieldSet) single() (int, bool) {
+	if len(f.values) != 1 {
+		return 0, false
+	}
+	return f.values[0], true
+}
+
+// every returns n if the field matches every n-th value from its minimum,
+// as "*/n" does, or 0 otherwise
+func (f fieldSet) every() int {
+	if len(f.values) < 2 || f.values[0] != f.min {
+		return 0
+	}
+	n := f.values[1] - f.values[0]
+	for i := 2; i < len(f.values); i++ {
+		if f.values[i]-f.values[i-1] != n {
+			return 0
+		}
+	}
+	if n < 2 || f.values[len(f.values)-1]+n <= f.max {
+		return 0
+	}
+	return n
+}
+
+// text lists the values, collapsing runs of three or more into ranges
+func (f fieldSet) text(name func(int) string) string {
+	if name == nil {
+		name = func(v int) string { return fmt.Sprint(v) }
+	}
+	var parts []string
+	for i := 0; i < len(f.values); {
+		j := i
+		for j+1 < len(f.values) && f.values[j+1] == f.values[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			parts = append(parts, name(f.values[i])+" through "+name(f.values[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				parts = append(parts, name(f.values[k]))
+			}
+		}
+		i = j + 1
+	}
+	return joinWords(parts)
+}
+
+func plural(f fieldSet, unit string) string {
+	if len(f.values) == 1 {
+		return unit
+	}
+	return unit + "s"
+}
+
+// joinWords joins words as "a, b and c"
+func joinWords(words []string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
+
+// clockText formats a time of day as "09:30", with seconds only when set
+func clockText(hour, min, sec int) string {
+	if sec != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hour, min, sec)
+	}
+	return fmt.Sprintf("%02d:%02d", hour, min)
+}
+
+// describeJob describes a job's spec for ListJobs, empty if it has none
+func describeJob(spec string) string {
+	text, err := DescribeSpec(spec)
+	if err != nil {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,42 @@
+package better_cron
+
+import "testing"
+
+func TestDescribeSpec(t *testing.T) {
+	tests := []struct {
+		spec, want string
+	}{
+		{"0 30 9 * * 1-5", "At 09:30 on Monday through Friday"},
+		{"0 0 9,17 * * *", "At 09:00 and 17:00"},
+		{"@daily", "At 00:00"},
+		{"@hourly", "Every hour"},
+		{"@every 5m", "Every 5m"},
+		{"0 */15 * * * *", "Every 15 minutes"},
+		{"0 0 */6 * * *", "Every 6 hours"},
+		{"0 0 9-17 * * *", "Every hour between 09:00 and 17:59"},
+		{"0 0 9 1 * *", "At 09:00 on day 1 of the month"},
+		{"0 0 9 * 1,6 *", "At 09:00 in January and June"},
+		{"TZ=Europe/Berlin 0 0 9 * * *", "At 09:00 (Europe/Berlin)"},
+		{"every day at noon", "every day at noon"},
+		{"0 0 12 L * ?", "0 0 12 L * ?"},
+		{"R/2026-03-10T00:00:00Z/PT6H", "R/2026-03-10T00:00:00Z/PT6H"},
+	}
+	for _, tt := range tests {
+		got, err := DescribeSpec(tt.spec)
+		if err != nil {
+			t.Errorf("DescribeSpec(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("DescribeSpec(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeSpecInvalid(t *testing.T) {
+	for _, spec := range []string{"", "bogus", "0 0 25 * * *"} {
+		if got, err := DescribeSpec(spec); err == nil {
+			t.Errorf("DescribeSpec(%q) = %q, want an error", spec, got)
+		}
+	}
+}
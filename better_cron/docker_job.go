@@ -0,0 +1,300 @@
+package better_cron
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DockerResult is the run result recorded for a DockerJob
+type DockerResult struct {
+	ContainerID string
+	ExitCode    int
+	Stdout      string
+	Stderr      string
+	// Truncated is set when an output stream exceeded MaxOutput
+	Truncated bool
+}
+
+// DockerJob is a job that runs a container per fire through the Docker
+// Engine API and maps the container exit code to success or failure
+type DockerJob struct {
+	Image string
+	Cmd   []string
+	Env   []string
+	// Memory limits the container memory in bytes; 0 means unlimited
+	Memory int64
+	// CPUs limits the container to a number of CPUs; 0 means unlimited
+	CPUs float64
+	// Host is the Docker daemon address; empty uses $DOCKER_HOST or the
+	// local unix socket
+	Host string
+	// MaxOutput caps the bytes kept per stream; 0 means 1 MiB
+	MaxOutput int
+}
+
+// Run runs the container with a background context
+func (j *DockerJob) Run() { _, _ = j.RunResult(context.Background()) }
+
+// RunResult creates, starts and waits for the container, collecting its
+// logs. The container is killed if ctx is done and removed afterwards.
+func (j *DockerJob) RunResult(ctx context.Context) (interface{}, error) {
+	client, err := newDockerClient(j.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := client.createContainer(ctx, j)
+	if err != nil {
+		return nil, err
+	}
+	result := DockerResult{ContainerID: id, ExitCode: -1}
+
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = client.call(cleanupCtx, http.MethodDelete, "/containers/"+id+"?force=1", nil, nil)
+	}()
+
+	if err := client.call(ctx, http.MethodPost, "/containers/"+id+"/start", nil, nil); err != nil {
+		return result, err
+	}
+
+	limit := j.MaxOutput
+	if limit <= 0 {
+		limit = defaultMaxOutput
+	}
+	stdout := &limitedBuffer{limit: limit}
+	stderr := &limitedBuffer{limit: limit}
+	logsDone := make(chan struct{})
+	go func() {
+		defer close(logsDone)
+		client.streamLogs(ctx, id, stdout, stderr)
+	}()
+
+	var wait struct {
+		StatusCode int
+		Error      *struct{ Message string }
+	}
+	err = client.call(ctx, http.MethodPost, "/containers/"+id+"/wait", nil, &wait)
+	if ctx.Err() != nil {
+		killCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_ = client.call(killCtx, http.MethodPost, "/containers/"+id+"/kill", nil, nil)
+		cancel()
+	}
+	<-logsDone
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	result.Truncated = stdout.truncated || stderr.truncated
+
+	switch {
+	case ctx.Err() != nil:
+		return result, fmt.Errorf("container %s killed: %w", shortID(id), ctx.Err())
+	case err != nil:
+		return result, err
+	case wait.Error != nil && wait.Error.Message != "":
+		return result, fmt.Errorf("container %s: %s", shortID(id), wait.Error.Message)
+	}
+
+	result.ExitCode = wait.StatusCode
+	if wait.StatusCode != 0 {
+		return result, fmt.Errorf("container %s exited with code %d", shortID(id), wait.StatusCode)
+	}
+	return result, nil
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// dockerClient is a minimal Docker Engine API client
+type dockerClient struct {
+	http *http.Client
+	base string
+}
+
+func newDockerClient(host string) (*dockerClient, error) {
+	if host == "" {
+		host = os.Getenv("DOCKER_HOST")
+	}
+	if host == "" {
+		host = "unix:///var/run/docker.sock"
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker host %q: %w", host, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		return &dockerClient{http: &http.Client{Transport: transport}, base: "http://docker"}, nil
+	case "tcp", "http":
+		return &dockerClient{http: &http.Client{}, base: "http://" + u.Host}, nil
+	default:
+		return nil, fmt.Errorf("unsupported docker host scheme %q", u.Scheme)
+	}
+}
+
+// call sends a request and decodes a JSON response into out if non-nil
+func (c *dockerClient) call(ctx context.Context, method, path string, body, out interface{}) error {
+	resp, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+func (c *dockerClient) do(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var apiErr struct{ Message string }
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return nil, &dockerError{status: resp.StatusCode, message: apiErr.Message}
+	}
+	return resp, nil
+}
+
+type dockerError struct {
+	status  int
+	message string
+}
+
+func (e *dockerError) Error() string {
+	return fmt.Sprintf("docker api status %d: %s", e.status, e.message)
+}
+
+// createContainer creates the job's container, pulling the image once if
+// the daemon doesn't have it yet
+func (c *dockerClient) createContainer(ctx context.Context, j *DockerJob) (string, error) {
+	spec := map[string]interface{}{
+		"Image": j.Image,
+		"Cmd":   j.Cmd,
+		"Env":   j.Env,
+		"HostConfig": map[string]interface{}{
+			"Memory":   j.Memory,
+			"NanoCpus": int64(j.CPUs * 1e9),
+		},
+	}
+
+	var created struct{ Id string }
+	err := c.call(ctx, http.MethodPost, "/containers/create", spec, &created)
+	if de, ok := err.(*dockerError); ok && de.status == http.StatusNotFound {
+		if err := c.pull(ctx, j.Image); err != nil {
+			return "", err
+		}
+		err = c.call(ctx, http.MethodPost, "/containers/create", spec, &created)
+	}
+	if err != nil {
+		return "", fmt.Errorf("create container from %s: %w", j.Image, err)
+	}
+	return created.Id, nil
+}
+
+func (c *dockerClient) pull(ctx context.Context, image string) error {
+	ref, tag := image, "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		ref, tag = image[:i], image[i+1:]
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "/images/create?fromImage="+url.QueryEscape(ref)+"&tag="+url.QueryEscape(tag), nil)
+	if err != nil {
+		return fmt.Errorf("pull %s: %w", image, err)
+	}
+	defer resp.Body.Close()
+
+	// The pull only completes once its progress stream is drained, and a
+	// failure part way is reported in the stream rather than the status
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var progress struct {
+			Error       string `json:"error"`
+			ErrorDetail struct {
+				Message string `json:"message"`
+			} `json:"errorDetail"`
+		}
+		err := decoder.Decode(&progress)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("pull %s: %w", image, err)
+		}
+		if progress.ErrorDetail.Message != "" {
+			return fmt.Errorf("pull %s: %s", image, progress.ErrorDetail.Message)
+		}
+		if progress.Error != "" {
+			return fmt.Errorf("pull %s: %s", image, progress.Error)
+		}
+	}
+}
+
+// streamLogs follows the container logs, demultiplexing stdout and stderr
+func (c *dockerClient) streamLogs(ctx context.Context, id string, stdout, stderr io.Writer) {
+	resp, err := c.do(ctx, http.MethodGet, "/containers/"+id+"/logs?follow=1&stdout=1&stderr=1", nil)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	var header [8]byte
+	for {
+		if _, err := io.ReadFull(resp.Body, header[:]); err != nil {
+			return
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		out := stdout
+		if header[0] == 2 {
+			out = stderr
+		}
+		if _, err := io.CopyN(out, resp.Body, size); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package better_cron
+
+import (
+	"sort"
+	"time"
+)
+
+// WithDryRun makes the scheduler log every fire, and emit it as a skipped
+// event with reason "dry run", instead of running the job. Time windows and
+// calendars still apply, so the log shows exactly what would have run.
+func WithDryRun() Option {
+	return func(ec *EnhancedCron) {
+		ec.dryRun = true
+	}
+}
+
+// SimulatedFire is one fire found by Simulate
+type SimulatedFire struct {
+	Job  string
+	Time time.Time
+	// SkipReason is set when the job's windows or calendar would skip the fire
+	SkipReason string
+}
+
+// Simulate computes every fire of every registered job in (from, to] without
+// running anything, sorted by time. Each fire is also logged, so a staging
+// deploy can be checked against the expected schedule.
+func (ec *EnhancedCron) Simulate(from, to time.Time) []SimulatedFire {
+	ec.mu.RLock()
+	entries := make([]*jobEntry, 0, len(ec.jobs))
+	for _, entry := range ec.jobs {
+		entries = append(entries, entry)
+	}
+	ec.mu.RUnlock()
+
+	var fires []SimulatedFire
+	for _, entry := range entries {
+		t := from
+		for i := 0; i < maxPreviewScan; i++ {
+			if t = entry.next(t); t.IsZero() || t.After(to) {
+				break
+			}
+			fires = append(fires, SimulatedFire{Job: entry.name, Time: t, SkipReason: entry.skipReason(t)})
+		}
+	}
+	sort.Slice(fires, func(i, j int) bool {
+		if !fires[i].Time.Equal(fires[j].Time) {
+			return fires[i].Time.Before(fires[j].Time)
+		}
+		return fires[i].Job < fires[j].Job
+	})
+
+	for _, f := range fires {
+		if f.SkipReason != "" {
+			ec.logger.Info("dry run: job %s would be skipped at %s: %s", f.Job, f.Time.Format(time.RFC3339), f.SkipReason)
+			continue
+		}
+		ec.logger.Info("dry run: job %s would run at %s", f.Job, f.Time.Format(time.RFC3339))
+	}
+	return fires
+}
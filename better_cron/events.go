@@ -0,0 +1,225 @@
+package better_cron
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType identifies a job lifecycle transition
+type EventType int
+
+const (
+	EventJobStarted EventType = iota
+	EventJobCompleted
+	EventJobFailed
+	EventJobCancelled
+	EventJobRetrying
+	EventJobSkipped
+	EventBreakerOpened
+	EventBreakerHalfOpen
+	EventBreakerClosed
+	EventJobDeadLettered
+	EventJobExpired
+	EventSLAAtRisk
+	EventSLAMissed
+	EventJobProgress
+	EventJobInterrupted
+	EventJobPaused
+	EventJobResumed
+	EventScheduleTight
+)
+
+// Convert EventType to string
+func (t EventType) String() string {
+	return [...]string{"started", "completed", "failed", "cancelled", "retrying", "skipped",
+		"breaker_opened", "breaker_half_open", "breaker_closed", "dead_lettered", "expired", "sla_at_risk", "sla_missed", "progress", "interrupted", "paused", "resumed", "schedule_tight"}[t]
+}
+
+// JobEvent describes a single lifecycle transition of a job run
+type JobEvent struct {
+	Type     EventType
+	Job      string
+	Tags     []string
+	Time     time.Time
+	Metadata JobMetadata
+
+	// Reason explains why a fire was skipped
+	Reason string
+}
+
+// HasTag reports whether the event's job carries the given tag
+func (e JobEvent) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// EventSink receives job lifecycle events. Sinks are called synchronously
+// from the job goroutine, so they should return quickly.
+type EventSink interface {
+	HandleEvent(event JobEvent)
+}
+
+// EventSinkFunc adapts a plain function to the EventSink interface
+type EventSinkFunc func(event JobEvent)
+
+// HandleEvent calls f(event)
+func (f EventSinkFunc) HandleEvent(event JobEvent) {
+	f(event)
+}
+
+// WithEventSink registers a sink that receives every job event
+func WithEventSink(sink EventSink) Option {
+	return func(ec *EnhancedCron) {
+		ec.sinks = append(ec.sinks, sink)
+	}
+}
+
+// WithNotifier registers a sink that receives only this job's events, in
+// addition to the scheduler-wide sinks
+func WithNotifier(sink EventSink) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.sinks = append(cfg.sinks, sink)
+	}
+}
+
+// WithNamedNotifier registers a sink under a name that job definitions can
+// refer to in their notifications list. Named notifiers only receive the
+// events of jobs that reference them.
+func WithNamedNotifier(name string, sink EventSink) Option {
+	return func(ec *EnhancedCron) {
+		ec.notifiers[name] = sink
+	}
+}
+
+// emit delivers an event built from the run metadata to all registered sinks
+func (ec *EnhancedCron) emit(eventType EventType, entry *jobEntry, metadata *JobMetadata) {
+	ec.dispatch(entry, JobEvent{
+		Type:     eventType,
+		Job:      entry.name,
+		Tags:     entry.cfg.tags,
+		Time:     ec.clock.Now(),
+		Metadata: *metadata,
+	})
+}
+
+// emitSkipped reports a fire that was not executed and why
+func (ec *EnhancedCron) emitSkipped(entry *jobEntry, reason string) {
+	ec.debug("skipped", "job", entry.name, "reason", reason)
+	ec.dispatch(entry, JobEvent{
+		Type:     EventJobSkipped,
+		Job:      entry.name,
+		Tags:     entry.cfg.tags,
+		Time:     ec.clock.Now(),
+		Metadata: JobMetadata{ID: entry.id, Name: entry.name, Status: StatusIdle},
+		Reason:   reason,
+	})
+}
+
+// dispatch hands an event to every scheduler-wide sink and to the job's own sinks
+func (ec *EnhancedCron) dispatch(entry *jobEntry, event JobEvent) {
+	ec.auditEvent(event)
+	for _, sink := range ec.sinks {
+		sink.HandleEvent(event)
+	}
+	for _, sink := range entry.cfg.sinks {
+		sink.HandleEvent(event)
+	}
+}
+
+// eventForStatus maps the final status of a run to its terminal event
+func eventForStatus(status JobStatus) EventType {
+	switch status {
+	case StatusFailed:
+		return EventJobFailed
+	case StatusCancelled:
+		return EventJobCancelled
+	default:
+		return EventJobCompleted
+	}
+}
+
+// EventSerializer encodes a JobEvent for transport to an external system
+type EventSerializer func(event JobEvent) ([]byte, error)
+
+// jsonEvent is the wire representation used by JSONEventSerializer
+type jsonEvent struct {
+	Type      string    `json:"type"`
+	Job       string    `json:"job"`
+	RunID     string    `json:"run_id,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Time      time.Time `json:"time"`
+	Status    string    `json:"status"`
+	Attempt   int       `json:"attempt,omitempty"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Error     string    `json:"error,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Progress  *float64  `json:"progress,omitempty"`
+	Step      string    `json:"step,omitempty"`
+}
+
+// JSONEventSerializer encodes an event as a flat JSON object
+func JSONEventSerializer(event JobEvent) ([]byte, error) {
+	out := jsonEvent{
+		Type:      event.Type.String(),
+		Job:       event.Job,
+		RunID:     event.Metadata.RunID,
+		Tags:      event.Tags,
+		Time:      event.Time,
+		Status:    event.Metadata.Status.String(),
+		Attempt:   event.Metadata.Attempt,
+		Reason:    event.Reason,
+		StartTime: event.Metadata.StartTime,
+		EndTime:   event.Metadata.EndTime,
+	}
+	if event.Metadata.Error != nil {
+		out.Error = event.Metadata.Error.Error()
+	}
+	if p := event.Metadata.Progress; !p.Updated.IsZero() {
+		out.Progress = &p.Percent
+		out.Step = p.Step
+	}
+	return json.Marshal(out)
+}
+
+// sinkConfig holds settings shared by the message broker sinks
+type sinkConfig struct {
+	serializer EventSerializer
+	logger     Logger
+}
+
+// SinkOption represents configuration options for message broker sinks
+type SinkOption func(*sinkConfig)
+
+// WithSerializer sets the encoder used for published events
+func WithSerializer(serializer EventSerializer) SinkOption {
+	return func(cfg *sinkConfig) {
+		cfg.serializer = serializer
+	}
+}
+
+// WithSinkLogger sets a logger for publish errors
+func WithSinkLogger(logger Logger) SinkOption {
+	return func(cfg *sinkConfig) {
+		cfg.logger = logger
+	}
+}
+
+func newSinkConfig(opts []SinkOption) sinkConfig {
+	cfg := sinkConfig{serializer: JSONEventSerializer}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// logError reports a sink failure when a logger is configured
+func (cfg sinkConfig) logError(format string, args ...interface{}) {
+	if cfg.logger != nil {
+		cfg.logger.Error(format, args...)
+	}
+}
@@ -0,0 +1,144 @@
+package better_cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// WithMaxRuns retires the job after n runs have started: the run that
+// reaches the limit deregisters the job, and an EventJobExpired follows once
+// it finishes
+func WithMaxRuns(n int) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.maxRuns = n
+	}
+}
+
+// WithEndAt stops the job from firing at or after end. The job is then
+// removed from the scheduler and an EventJobExpired is emitted, even if it
+// had no fire left before end.
+func WithEndAt(end time.Time) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.endAt = end
+	}
+}
+
+// untilSchedule cuts a schedule off at an end time
+type untilSchedule struct {
+	cron.Schedule
+	end time.Time
+}
+
+// Next returns the schedule's next fire, or the zero time once it would
+// fall at or after the end
+func (s untilSchedule) Next(t time.Time) time.Time {
+	next := s.Schedule.Next(t)
+	if !s.end.IsZero() && !next.Before(s.end) {
+		return time.Time{}
+	}
+	return next
+}
+
+// atSchedule fires once at a fixed time
+type atSchedule time.Time
+
+// Next returns the fixed time while it is still ahead of t
+func (s atSchedule) Next(t time.Time) time.Time {
+	if at := time.Time(s); t.Before(at) {
+		return at
+	}
+	return time.Time{}
+}
+
+// next returns the job's next fire after t, honouring its end time
+func (entry *jobEntry) next(t time.Time) time.Time {
+	return untilSchedule{entry.schedule, entry.cfg.endAt}.Next(t)
+}
+
+// scheduleExpiry registers the entry that removes the job at its end time;
+// callers hold ec.mu
+func (ec *EnhancedCron) scheduleExpiry(entry *jobEntry) {
+	if entry.cfg.endAt.IsZero() {
+		return
+	}
+	entry.expiry = ec.sched.Schedule(atSchedule(entry.cfg.endAt), cron.FuncJob(func() {
+		if ec.deregister(entry) {
+			ec.emitExpired(entry, "end time reached")
+		}
+	}))
+}
+
+// claimRun counts a run against the job's run limit. It reports false when
+// the limit was already used up, and last when this run is the final one,
+// after which the caller removes the job once the run starts.
+func (ec *EnhancedCron) claimRun(entry *jobEntry) (ok, last bool) {
+	if entry.cfg.maxRuns <= 0 {
+		return true, false
+	}
+
+	entry.mu.Lock()
+	if entry.runs >= entry.cfg.maxRuns {
+		entry.mu.Unlock()
+		ec.emitSkipped(entry, "max runs reached")
+		return false, false
+	}
+	entry.runs++
+	last = entry.runs == entry.cfg.maxRuns
+	entry.mu.Unlock()
+	return true, last
+}
+
+// releaseRun gives back the run claimed by a fire that did not start
+func (ec *EnhancedCron) releaseRun(entry *jobEntry, claimed bool) {
+	if !claimed || entry.cfg.maxRuns <= 0 {
+		return
+	}
+	entry.mu.Lock()
+	entry.runs--
+	entry.mu.Unlock()
+}
+
+// deregister removes the job if entry is still its current registration
+func (ec *EnhancedCron) deregister(entry *jobEntry) bool {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	current, ok := ec.jobs[entry.name]
+	if !ok || current.jobState != entry.jobState {
+		return false
+	}
+	ec.unschedule(current)
+	delete(ec.jobs, entry.name)
+	return true
+}
+
+// unschedule drops the job's scheduler entries; callers hold ec.mu
+func (ec *EnhancedCron) unschedule(entry *jobEntry) {
+	ec.sched.Remove(entry.id)
+	if entry.expiry != 0 {
+		ec.sched.Remove(entry.expiry)
+	}
+	for _, id := range entry.slaIDs {
+		ec.sched.Remove(id)
+	}
+}
+
+// emitExpired reports that a job was retired and will not fire again
+func (ec *EnhancedCron) emitExpired(entry *jobEntry, reason string) {
+	ec.logger.Info("job %s expired: %s", entry.name, reason)
+	ec.dispatch(entry, JobEvent{
+		Type:     EventJobExpired,
+		Job:      entry.name,
+		Tags:     entry.cfg.tags,
+		Time:     ec.clock.Now(),
+		Metadata: JobMetadata{ID: entry.id, Name: entry.name, Status: StatusIdle},
+		Reason:   reason,
+	})
+}
+
+// maxRunsReason describes a job retired by WithMaxRuns
+func maxRunsReason(n int) string {
+	return fmt.Sprintf("reached %d runs", n)
+}
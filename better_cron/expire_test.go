@@ -0,0 +1,53 @@
+package better_cron_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cron_test/bcrontest"
+	"cron_test/better_cron"
+)
+
+// flakyStore fails every write while down is set
+type flakyStore struct {
+	*better_cron.MemoryStore
+	down atomic.Bool
+}
+
+func (s *flakyStore) Put(key string, value []byte) error {
+	if s.down.Load() {
+		return errors.New("store unavailable")
+	}
+	return s.MemoryStore.Put(key, value)
+}
+
+func TestMaxRunsCountsOnlyStartedRuns(t *testing.T) {
+	store := &flakyStore{MemoryStore: better_cron.NewMemoryStore()}
+	r := bcrontest.New(t, epoch, better_cron.WithStore(store), better_cron.WithRunJournal())
+	defer r.Close()
+
+	if _, err := r.Cron.AddJob("@every 1m", better_cron.ErrorFuncJob(func(context.Context) error { return nil }), "once",
+		better_cron.WithMaxRuns(1)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	// The journal cannot be written, so the fire is skipped and must not
+	// use up the job's only run
+	store.down.Store(true)
+	r.Advance(time.Minute)
+	r.ExpectRuns(t, "once", 0, epoch, epoch.Add(time.Minute))
+	if jobs := r.Cron.ListJobs(); len(jobs) != 1 {
+		t.Fatalf("%d jobs registered after a skipped fire, want 1", len(jobs))
+	}
+
+	store.down.Store(false)
+	r.Advance(time.Minute)
+	r.ExpectRuns(t, "once", 1, epoch, epoch.Add(2*time.Minute))
+	if jobs := r.Cron.ListJobs(); len(jobs) != 0 {
+		t.Errorf("%d jobs registered after the last run, want 0", len(jobs))
+	}
+}
@@ -0,0 +1,223 @@
+package better_cron
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ImportMode decides what ImportJobs does with jobs already registered
+type ImportMode int
+
+const (
+	// ImportMerge adds the imported jobs and updates those that already
+	// exist, leaving every other job alone
+	ImportMerge ImportMode = iota
+	// ImportReplace also removes every job the import does not contain
+	ImportReplace
+)
+
+// Convert ImportMode to string
+func (m ImportMode) String() string {
+	switch m {
+	case ImportMerge:
+		return "merge"
+	case ImportReplace:
+		return "replace"
+	}
+	return fmt.Sprintf("ImportMode(%d)", int(m))
+}
+
+// ActorImport is the audit actor of jobs registered by ImportJobs
+const ActorImport = "import"
+
+// ExportJobs dumps every registered job as a JSON job definition file, sorted
+// by name, for versioning in git or restoring into another instance with
+// ImportJobs or LoadJobsFromFile. Jobs loaded from a file are exported as
+// defined there. Jobs added in code are exported as type "func" jobs naming
+// the job itself, so the importing instance must register their bodies with
+// RegisterJobFunc. Their settings carry over as far as a definition can
+// express them; options given as functions, such as preconditions, custom
+// backoff policies, calendars and notifiers, are dropped and logged per job.
+// Jobs on a custom Schedule have no spec and are left out.
+func (ec *EnhancedCron) ExportJobs() ([]byte, error) {
+	ec.configMu.Lock()
+	defined := make(map[string]JobDefinition)
+	for _, defs := range ec.configDefs {
+		for name, def := range defs {
+			defined[name] = def
+		}
+	}
+	ec.configMu.Unlock()
+
+	ec.mu.RLock()
+	file := JobsFile{Jobs: make([]JobDefinition, 0, len(ec.jobs))}
+	for name, entry := range ec.jobs {
+		if def, ok := defined[name]; ok {
+			file.Jobs = append(file.Jobs, def)
+			continue
+		}
+		if _, err := ValidateSpec(entry.spec); err != nil {
+			ec.logger.Info("not exporting job %s: its schedule has no spec", name)
+			continue
+		}
+		def, dropped := entry.definition()
+		if len(dropped) > 0 {
+			ec.logger.Info("exporting job %s without its %s", name, strings.Join(dropped, ", "))
+		}
+		file.Jobs = append(file.Jobs, def)
+	}
+	ec.mu.RUnlock()
+
+	sort.Slice(file.Jobs, func(i, j int) bool {
+		return file.Jobs[i].qualifiedName() < file.Jobs[j].qualifiedName()
+	})
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// definition describes a job added in code as a "func" job definition,
+// along with the options it cannot express
+func (entry *jobEntry) definition() (JobDefinition, []string) {
+	cfg := entry.cfg
+	namespace, name := SplitName(entry.name)
+	def := JobDefinition{
+		Name:             name,
+		Namespace:        namespace,
+		Spec:             entry.spec,
+		Type:             "func",
+		Func:             entry.name,
+		Timeout:          Duration(cfg.timeout),
+		GracePeriod:      Duration(cfg.grace),
+		RunOnStart:       cfg.runOnStart,
+		MaxRuns:          cfg.maxRuns,
+		EndAt:            cfg.endAt,
+		Tags:             cfg.tags,
+		Params:           cfg.params,
+		RerunInterrupted: cfg.rerunInterrupted,
+		FailureThreshold: cfg.failureThreshold,
+		ScheduleBackoff:  Duration(cfg.backoffMax),
+		Upstreams:        cfg.upstreams,
+		Jitter:           Duration(cfg.jitter),
+		Heartbeat:        Duration(cfg.heartbeat),
+	}
+	if cfg.location != nil {
+		def.Timezone = cfg.location.String()
+	}
+	for _, w := range cfg.allowed {
+		def.AllowedWindows = append(def.AllowedWindows, w.String())
+	}
+	for _, w := range cfg.blackouts {
+		def.BlackoutWindows = append(def.BlackoutWindows, w.String())
+	}
+	if cfg.budget > 0 {
+		def.Budget = &BudgetDefinition{Limit: Duration(cfg.budget), Window: Duration(cfg.budgetWindow)}
+	}
+	if cfg.slaSpec != "" {
+		def.SLA = &SLADefinition{Deadline: cfg.slaSpec, Warning: Duration(cfg.slaWarning)}
+	}
+	if cfg.maxAttempts > 1 {
+		def.Retries = &RetryDefinition{Attempts: cfg.maxAttempts}
+	}
+	if cfg.overlap != OverlapAllow || cfg.maxInstances > 0 {
+		def.Overlap = &OverlapDefinition{
+			Policy:       [...]string{"allow", "skip", "queue"}[cfg.overlap],
+			MaxInstances: cfg.maxInstances,
+		}
+		if cfg.overlap == OverlapQueue {
+			def.Overlap.QueueDepth = cfg.queueDepth
+			def.Overlap.Overflow = [...]string{"drop_newest", "drop_oldest"}[cfg.overflow]
+		}
+	}
+	if cfg.breakerThreshold > 0 {
+		def.Breaker = &BreakerDefinition{Threshold: cfg.breakerThreshold, Cooldown: Duration(cfg.breakerCooldown)}
+	}
+
+	var dropped []string
+	drop := func(option string, isSet bool) {
+		if isSet {
+			dropped = append(dropped, option)
+		}
+	}
+	drop("retry backoff", cfg.maxAttempts > 1 && cfg.backoff != nil)
+	drop("calendar", cfg.calendar != nil)
+	drop("notifiers", len(cfg.sinks) > 0)
+	drop("logger", cfg.logger != nil)
+	drop("preconditions", len(cfg.preconditions) > 0)
+	drop("finalizers", len(cfg.finalizers) > 0)
+	drop("idempotency key", cfg.idempotencyKey != nil)
+	drop("fingerprint", cfg.fingerprint != nil)
+	drop("tight schedule policy", cfg.tightRatio > 0)
+	drop("load smoothing opt-out", cfg.noSmoothing)
+	return def, dropped
+}
+
+// ImportJobs registers the jobs of a JSON job definition file, as written by
+// ExportJobs. Every definition is validated before any job changes; existing
+// jobs are updated in place and, with ImportReplace, jobs missing from data
+// are removed. Runs in flight are never interrupted.
+func (ec *EnhancedCron) ImportJobs(data []byte, mode ImportMode) error {
+	if err := ec.writable(); err != nil {
+		return err
+	}
+	err := ec.importJobs(data, mode)
+	ec.recordAction(AdminAction{
+		Actor:  ActorAPI,
+		Action: "import",
+		Params: map[string]string{"mode": mode.String()},
+	}, err)
+	return err
+}
+
+func (ec *EnhancedCron) importJobs(data []byte, mode ImportMode) error {
+	if mode != ImportMerge && mode != ImportReplace {
+		return fmt.Errorf("unknown import mode %d", mode)
+	}
+
+	var file JobsFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return fmt.Errorf("parse imported jobs: %w", err)
+	}
+	jobs, err := ec.buildDefinitions(file.Jobs)
+	if err != nil {
+		return err
+	}
+
+	actor := WithAuditActor(ActorImport)
+	imported := make(map[string]bool, len(jobs))
+	for _, b := range jobs {
+		name := b.def.qualifiedName()
+		imported[name] = true
+		if !b.def.EndAt.IsZero() && !ec.clock.Now().Before(b.def.EndAt) {
+			continue
+		}
+
+		ec.mu.RLock()
+		_, exists := ec.jobs[name]
+		ec.mu.RUnlock()
+		if exists {
+			_, err = ec.UpdateJob(b.def.Spec, b.job, name, append(b.opts, actor)...)
+		} else {
+			_, err = ec.AddJob(b.def.Spec, b.job, name, append(b.opts, actor)...)
+		}
+		if err != nil {
+			return fmt.Errorf("import job %s: %w", name, err)
+		}
+	}
+
+	if mode == ImportReplace {
+		for _, job := range ec.ListJobs() {
+			if imported[job.Name] {
+				continue
+			}
+			if err := ec.removeJob(job.Name, ActorImport); err != nil {
+				ec.logger.Error("remove job %s: %v", job.Name, err)
+			}
+		}
+	}
+	ec.logger.Info("imported %d jobs", len(jobs))
+	return nil
+}
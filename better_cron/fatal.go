@@ -0,0 +1,32 @@
+package better_cron
+
+import (
+	"context"
+	"sync"
+)
+
+// ShutdownOnFatal returns a fatal action for custom_logger that shuts the
+// scheduler down in the given mode instead of exiting on the spot, then
+// calls exit(1):
+//
+//	logger.SetFatalAction(ec.ShutdownOnFatal(better_cron.ShutdownDrain, os.Exit))
+//
+// The shutdown runs in the background, so a job logging a fatal error is
+// not left waiting for itself, and the logging call returns. A nil exit
+// leaves the process running once the scheduler has stopped.
+func (ec *EnhancedCron) ShutdownOnFatal(mode ShutdownMode, exit func(code int)) func(err error) {
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			go func() {
+				ec.logger.Error("fatal error, shutting down: %v", err)
+				if err := ec.Shutdown(context.Background(), mode); err != nil {
+					ec.logger.Error("shutdown after fatal error: %v", err)
+				}
+				if exit != nil {
+					exit(1)
+				}
+			}()
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package better_cron
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+)
+
+// Finalizer is called with the final metadata of a run
+type Finalizer func(ctx context.Context, metadata JobMetadata)
+
+// WithFinalizer calls fn after every run of the job, whether it completed,
+// failed, panicked or was cancelled, once the run's outcome is recorded and
+// its events emitted. It suits releasing external resources or writing
+// business-level completion records. The context carries the run's
+// identity and is bounded by the job's timeout, but is not cancelled with
+// the run. Fires that are skipped never run, so they are not finalized. A
+// panicking finalizer is recovered and logged.
+func WithFinalizer(fn Finalizer) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.finalizers = append(cfg.finalizers, fn)
+	}
+}
+
+// finalize calls the job's finalizers in order
+func (ec *EnhancedCron) finalize(entry *jobEntry, metadata JobMetadata, timeout time.Duration) {
+	if len(entry.cfg.finalizers) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(withRunIdentity(context.Background(), &metadata), timeout)
+	defer cancel()
+	for _, fn := range entry.cfg.finalizers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.logger.Error("finalizer of job %s panicked: %v\n%s", entry.name, r, debug.Stack())
+				}
+			}()
+			fn(ctx, metadata)
+		}()
+	}
+}
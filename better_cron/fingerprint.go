@@ -0,0 +1,96 @@
+package better_cron
+
+import (
+	"context"
+	"time"
+)
+
+const fingerprintPrefix = "fingerprints/"
+
+// ReasonCacheHit is the skip reason of a fire whose input is unchanged
+const ReasonCacheHit = "cache hit"
+
+// FingerprintFunc computes a fingerprint of a job's input, such as a hash of
+// the files it processes or the last modification time of a table
+type FingerprintFunc func(ctx context.Context) (string, error)
+
+// WithFingerprint memoizes the job on its input: before each run fp is
+// computed, and if it matches the fingerprint of the last successful run
+// the fire is skipped with ReasonCacheHit instead of reprocessing unchanged
+// data. The fingerprint of every run is recorded in its metadata, and with
+// a store the last successful one survives restarts. A fingerprint that
+// cannot be computed, or is empty, lets the run go ahead.
+func WithFingerprint(fp FingerprintFunc) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.fingerprint = fp
+	}
+}
+
+// checkFingerprint computes the fire's fingerprint, reporting false if it
+// matches the last successful run's and the fire must be skipped
+func (ec *EnhancedCron) checkFingerprint(entry *jobEntry, timeout time.Duration) (string, bool) {
+	if entry.cfg.fingerprint == nil {
+		return "", true
+	}
+
+	ctx, cancel := context.WithTimeout(ec.shutdownContext(), timeout)
+	defer cancel()
+	fingerprint, err := entry.cfg.fingerprint(ctx)
+	if err != nil {
+		ec.logger.Error("failed to fingerprint job %s, running it: %v", entry.name, err)
+		return "", true
+	}
+
+	if fingerprint == "" {
+		return "", true
+	}
+	if last, found := ec.lastFingerprint(entry); found && fingerprint == last {
+		ec.debug("cache hit", "job", entry.name, "fingerprint", fingerprint)
+		ec.emitSkipped(entry, ReasonCacheHit)
+		return fingerprint, false
+	}
+	return fingerprint, true
+}
+
+// lastFingerprint returns the fingerprint of the job's last successful run,
+// reporting false if there is none yet
+func (ec *EnhancedCron) lastFingerprint(entry *jobEntry) (string, bool) {
+	entry.mu.Lock()
+	fingerprint, found, loaded := entry.fingerprint, entry.fingerprintFound, entry.fingerprintLoaded
+	entry.mu.Unlock()
+	if found || loaded || ec.store == nil {
+		return fingerprint, found
+	}
+
+	data, found, err := ec.store.Get(fingerprintPrefix + entry.name)
+	if err != nil {
+		ec.logger.Error("failed to load fingerprint of job %s: %v", entry.name, err)
+		return "", false
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if !entry.fingerprintLoaded && !entry.fingerprintFound {
+		entry.fingerprintLoaded = true
+		if found && len(data) > 0 {
+			entry.fingerprint, entry.fingerprintFound = string(data), true
+		}
+	}
+	return entry.fingerprint, entry.fingerprintFound
+}
+
+// recordFingerprint keeps the fingerprint of a successful run
+func (ec *EnhancedCron) recordFingerprint(entry *jobEntry, metadata *JobMetadata) {
+	if metadata.Fingerprint == "" || metadata.Status != StatusCompleted {
+		return
+	}
+
+	entry.mu.Lock()
+	entry.fingerprint, entry.fingerprintFound = metadata.Fingerprint, true
+	entry.mu.Unlock()
+	if ec.store == nil {
+		return
+	}
+	if err := ec.store.Put(fingerprintPrefix+entry.name, []byte(metadata.Fingerprint)); err != nil {
+		ec.logger.Error("failed to store fingerprint of job %s: %v", entry.name, err)
+	}
+}
@@ -0,0 +1,40 @@
+package better_cron_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cron_test/bcrontest"
+	"cron_test/better_cron"
+)
+
+func TestFingerprintEmptyAlwaysRuns(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []better_cron.Option
+	}{
+		{"memory", nil},
+		{"store", []better_cron.Option{better_cron.WithStore(better_cron.NewMemoryStore())}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bcrontest.New(t, epoch, tc.opts...)
+			defer r.Close()
+
+			job := better_cron.ErrorFuncJob(func(context.Context) error { return nil })
+			empty := func(context.Context) (string, error) { return "", nil }
+			same := func(context.Context) (string, error) { return "v1", nil }
+			if _, err := r.Cron.AddJob("@every 1m", job, "empty", better_cron.WithFingerprint(empty)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Cron.AddJob("@every 1m", job, "same", better_cron.WithFingerprint(same)); err != nil {
+				t.Fatal(err)
+			}
+			r.Start()
+			r.Advance(3 * time.Minute)
+
+			r.ExpectRuns(t, "empty", 3, epoch, epoch.Add(3*time.Minute))
+			r.ExpectRuns(t, "same", 1, epoch, epoch.Add(3*time.Minute))
+		})
+	}
+}
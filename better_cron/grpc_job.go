@@ -0,0 +1,120 @@
+package better_cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// GRPCInvoker performs a unary gRPC call. A *grpc.ClientConn is adapted with
+//
+//	func(ctx context.Context, method string, req, reply interface{}) error {
+//		return conn.Invoke(ctx, method, req, reply)
+//	}
+//
+// Transport security such as mTLS is set up on the ClientConn.
+type GRPCInvoker func(ctx context.Context, method string, req, reply interface{}) error
+
+// GRPCMethod describes the message types of a gRPC method so requests can be
+// decoded from JSON and responses recorded as JSON
+type GRPCMethod struct {
+	NewRequest  func() interface{}
+	NewResponse func() interface{}
+	// Unmarshal decodes the JSON request; nil means encoding/json. Use a
+	// protojson wrapper for generated protobuf messages.
+	Unmarshal func(data []byte, v interface{}) error
+	// Marshal encodes the response for the run record; nil means encoding/json
+	Marshal func(v interface{}) ([]byte, error)
+}
+
+var grpcRegistry = struct {
+	sync.RWMutex
+	methods  map[string]GRPCMethod
+	invokers map[string]GRPCInvoker
+}{
+	methods:  make(map[string]GRPCMethod),
+	invokers: make(map[string]GRPCInvoker),
+}
+
+// RegisterGRPCMethod registers the message types of a full method name
+// such as "/billing.Reconciler/Reconcile"
+func RegisterGRPCMethod(fullMethod string, method GRPCMethod) {
+	grpcRegistry.Lock()
+	defer grpcRegistry.Unlock()
+	grpcRegistry.methods[fullMethod] = method
+}
+
+// RegisterGRPCInvoker registers the invoker used by GRPCJobs for target
+func RegisterGRPCInvoker(target string, invoker GRPCInvoker) {
+	grpcRegistry.Lock()
+	defer grpcRegistry.Unlock()
+	grpcRegistry.invokers[target] = invoker
+}
+
+// GRPCResult is the run result recorded for a GRPCJob
+type GRPCResult struct {
+	Target   string
+	Method   string
+	Response string
+	Latency  time.Duration
+}
+
+// GRPCJob is a job that invokes a unary gRPC method with a JSON request
+type GRPCJob struct {
+	Target  string
+	Method  string
+	Request string
+	// Invoker overrides the invoker registered for Target
+	Invoker GRPCInvoker
+}
+
+// Run performs the call with a background context
+func (j *GRPCJob) Run() { _, _ = j.RunResult(context.Background()) }
+
+// RunResult performs the call and returns its GRPCResult
+func (j *GRPCJob) RunResult(ctx context.Context) (interface{}, error) {
+	grpcRegistry.RLock()
+	method, ok := grpcRegistry.methods[j.Method]
+	invoker := j.Invoker
+	if invoker == nil {
+		invoker = grpcRegistry.invokers[j.Target]
+	}
+	grpcRegistry.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("grpc method %s is not registered", j.Method)
+	}
+	if invoker == nil {
+		return nil, fmt.Errorf("no grpc invoker for target %q", j.Target)
+	}
+
+	unmarshal, marshal := method.Unmarshal, method.Marshal
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	if marshal == nil {
+		marshal = json.Marshal
+	}
+
+	req, reply := method.NewRequest(), method.NewResponse()
+	if j.Request != "" {
+		if err := unmarshal([]byte(j.Request), req); err != nil {
+			return nil, fmt.Errorf("decode request for %s: %w", j.Method, err)
+		}
+	}
+
+	result := GRPCResult{Target: j.Target, Method: j.Method}
+	start := time.Now()
+	err := invoker(ctx, j.Method, req, reply)
+	result.Latency = time.Since(start)
+	if err != nil {
+		return result, fmt.Errorf("grpc %s on %s: %w", j.Method, j.Target, err)
+	}
+
+	if data, err := marshal(reply); err == nil {
+		result.Response = string(data)
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package better_cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithHeartbeat lets runs of the job outlive their timeout by calling
+// Heartbeat: each call moves the run's deadline to at least extend from
+// now. A run that stops sending heartbeats is cancelled once the deadline
+// passes, with a cause that wraps context.DeadlineExceeded.
+func WithHeartbeat(extend time.Duration) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.heartbeat = extend
+	}
+}
+
+// Heartbeat signals that the current run is alive and making progress,
+// extending its deadline if the job has WithHeartbeat. It does nothing
+// outside such a run.
+func Heartbeat(ctx context.Context) {
+	if hb, ok := ctx.Value(heartbeatKey).(*heartbeat); ok {
+		hb.beat()
+	}
+}
+
+// heartbeat is the extendable deadline of one run
+type heartbeat struct {
+	extend time.Duration
+	clock  Clock
+	cancel context.CancelCauseFunc
+
+	mu       sync.Mutex
+	deadline time.Time
+	expired  bool
+}
+
+// runContext returns the context a run executes under: one with a fixed
+// timeout, or one whose deadline heartbeats extend
+func (ec *EnhancedCron) runContext(parent context.Context, entry *jobEntry, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if entry.cfg.heartbeat <= 0 {
+		return context.WithTimeout(parent, timeout)
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+	hb := &heartbeat{extend: entry.cfg.heartbeat, clock: ec.clock, cancel: cancel, deadline: ec.clock.Now().Add(timeout)}
+	go hb.watch(ctx, timeout)
+	return context.WithValue(ctx, heartbeatKey, hb), func() {
+		cancel(nil)
+	}
+}
+
+func (hb *heartbeat) beat() {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	// The timer is left alone; when it fires it waits out the new deadline
+	if deadline := hb.clock.Now().Add(hb.extend); !hb.expired && deadline.After(hb.deadline) {
+		hb.deadline = deadline
+	}
+}
+
+// watch waits out the deadline until the run ends or misses a heartbeat
+func (hb *heartbeat) watch(ctx context.Context, wait time.Duration) {
+	for wait > 0 {
+		timer := hb.clock.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+		wait = hb.expire()
+	}
+}
+
+// expire cancels the run once the deadline has passed, or returns how long
+// is left of it
+func (hb *heartbeat) expire() time.Duration {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	// Heartbeats may have moved the deadline since the timer was set
+	if remaining := hb.deadline.Sub(hb.clock.Now()); remaining > 0 {
+		return remaining
+	}
+	hb.expired = true
+	hb.cancel(fmt.Errorf("%w: no heartbeat within %v", context.DeadlineExceeded, hb.extend))
+	return 0
+}
@@ -0,0 +1,56 @@
+package better_cron_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cron_test/better_cron"
+)
+
+func TestHeartbeatFollowsClock(t *testing.T) {
+	clock := better_cron.NewFakeClock(epoch)
+	ec := better_cron.NewEnhancedCron(better_cron.WithClock(clock))
+	defer ec.Shutdown(context.Background(), better_cron.ShutdownAbort)
+
+	runs := make(chan context.Context, 1)
+	job := better_cron.ErrorFuncJob(func(ctx context.Context) error {
+		runs <- ctx
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+	if _, err := ec.AddJob("@yearly", job, "beating",
+		better_cron.WithJobTimeout(time.Minute), better_cron.WithHeartbeat(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	ec.Start()
+	if err := ec.TriggerJob("beating"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := <-runs
+
+	// The scheduler's own timer and the run's deadline; a heartbeat at 00:45
+	// moves the deadline to 01:45
+	clock.BlockUntil(2)
+	clock.Advance(45 * time.Second)
+	better_cron.Heartbeat(ctx)
+	clock.Advance(45 * time.Second)
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("run cancelled at 01:30 despite a heartbeat: %v", err)
+	}
+
+	clock.BlockUntil(2)
+	clock.Advance(15 * time.Second)
+	select {
+	case <-ctx.Done():
+		if cause := context.Cause(ctx); !errors.Is(cause, context.DeadlineExceeded) {
+			t.Errorf("run cancelled with %v, want a missed heartbeat", cause)
+		}
+		if deadline, _ := better_cron.DeadlineFromContext(ctx); !deadline.Equal(epoch.Add(105 * time.Second)) {
+			t.Errorf("deadline %v, want %v", deadline, epoch.Add(105*time.Second))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run not cancelled once the extended deadline passed")
+	}
+}
@@ -0,0 +1,38 @@
+package better_cron
+
+// WithHistoryLimit sets how many finished runs are kept per job
+func WithHistoryLimit(limit int) Option {
+	return func(ec *EnhancedCron) {
+		ec.historyLimit = limit
+	}
+}
+
+// recordHistory appends a finished run to the job's history, dropping the
+// oldest runs beyond the history limit
+func (ec *EnhancedCron) recordHistory(entry *jobEntry, metadata *JobMetadata) {
+	if ec.historyLimit <= 0 {
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	entry.history = append(entry.history, *metadata)
+	if over := len(entry.history) - ec.historyLimit; over > 0 {
+		entry.history = append(entry.history[:0:0], entry.history[over:]...)
+	}
+}
+
+// GetJobHistory returns the finished runs of a job by name, oldest first
+func (ec *EnhancedCron) GetJobHistory(name string) ([]JobMetadata, bool) {
+	ec.mu.RLock()
+	entry, ok := ec.jobs[name]
+	ec.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return append([]JobMetadata(nil), entry.history...), true
+}
@@ -0,0 +1,112 @@
+package better_cron
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultMaxResponseBody caps how much of a response body an HTTPJob keeps
+const defaultMaxResponseBody = 64 << 10
+
+// HTTPResult is the run result recorded for an HTTPJob
+type HTTPResult struct {
+	StatusCode int
+	Latency    time.Duration
+	Body       string
+}
+
+// HTTPJob is a job that sends a single HTTP request under the run context.
+// URL, Headers and Body are expanded with ExpandTemplate for every run.
+type HTTPJob struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+	// ExpectedStatus lists the status codes counted as success; empty means any 2xx
+	ExpectedStatus []int
+	// MaxResponseBody caps the bytes of the response kept; 0 means 64 KiB
+	MaxResponseBody int64
+	Client          *http.Client
+}
+
+// Run sends the request with a background context
+func (j *HTTPJob) Run() { _, _ = j.RunResult(context.Background()) }
+
+// RunResult sends the request and returns its HTTPResult. An unexpected
+// status code is reported as an error.
+func (j *HTTPJob) RunResult(ctx context.Context) (interface{}, error) {
+	client := j.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	method := j.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	limit := j.MaxResponseBody
+	if limit <= 0 {
+		limit = defaultMaxResponseBody
+	}
+
+	url, err := ExpandTemplate(ctx, j.URL)
+	if err != nil {
+		return nil, fmt.Errorf("url: %w", err)
+	}
+	var body io.Reader
+	if j.Body != "" {
+		text, err := ExpandTemplate(ctx, j.Body)
+		if err != nil {
+			return nil, fmt.Errorf("body: %w", err)
+		}
+		body = strings.NewReader(text)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range j.Headers {
+		value, err := ExpandTemplate(ctx, v)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", k, err)
+		}
+		req.Header.Set(k, value)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return HTTPResult{Latency: time.Since(start)}, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	result := HTTPResult{
+		StatusCode: resp.StatusCode,
+		Latency:    time.Since(start),
+		Body:       string(data),
+	}
+	if err != nil {
+		return result, fmt.Errorf("read response from %s: %w", j.URL, err)
+	}
+
+	if !j.expected(resp.StatusCode) {
+		return result, fmt.Errorf("%s %s returned unexpected status %d", method, j.URL, resp.StatusCode)
+	}
+	return result, nil
+}
+
+func (j *HTTPJob) expected(code int) bool {
+	if len(j.ExpectedStatus) == 0 {
+		return code >= 200 && code <= 299
+	}
+	for _, c := range j.ExpectedStatus {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
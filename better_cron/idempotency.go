@@ -0,0 +1,104 @@
+package better_cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const idempotencyPrefix = "idempotency/"
+
+// IdempotencyKeyFunc computes the idempotency key of a fire of a job
+type IdempotencyKeyFunc func(job string, scheduled time.Time) string
+
+// DefaultIdempotencyKey keys a fire by the job name and its scheduled time
+func DefaultIdempotencyKey(job string, scheduled time.Time) string {
+	return job + "@" + scheduled.UTC().Format(time.RFC3339Nano)
+}
+
+// idempotencyRecord is what is stored for a key; a key whose run has not
+// completed yet is reserved by the instance running it
+type idempotencyRecord struct {
+	Key       string    `json:"key"`
+	RunID     string    `json:"run_id,omitempty"`
+	Instance  string    `json:"instance,omitempty"`
+	Completed bool      `json:"completed"`
+	Time      time.Time `json:"time"`
+}
+
+// WithIdempotency reserves the idempotency key of every fire of the job in
+// the store before it runs and skips fires whose key is already reserved or
+// completed, so a fire is executed once across restarts and failovers
+// between instances sharing the store. Keys are computed from the time the
+// fire was due, so the instances must share the job's schedule. key
+// defaults to DefaultIdempotencyKey when nil. Failed and cancelled runs
+// release their key and may run again; a crash mid-run leaves it reserved.
+// It needs WithStore, and a ClaimStore to keep two live instances from
+// both reserving a key.
+func WithIdempotency(key IdempotencyKeyFunc) JobOption {
+	return func(cfg *jobConfig) {
+		if key == nil {
+			key = DefaultIdempotencyKey
+		}
+		cfg.idempotencyKey = key
+	}
+}
+
+// checkIdempotency reserves the fire's key and reports false if it was
+// already reserved or completed
+func (ec *EnhancedCron) checkIdempotency(entry *jobEntry, f firing) (string, bool) {
+	if entry.cfg.idempotencyKey == nil {
+		return "", true
+	}
+
+	key := entry.cfg.idempotencyKey(entry.name, f.scheduled)
+	data, err := json.Marshal(idempotencyRecord{Key: key, Instance: ec.instance, Time: ec.clock.Now()})
+	claimed := false
+	if err == nil {
+		claimed, err = claim(ec.store, idempotencyPrefix+key, data)
+	}
+	if err != nil {
+		ec.logger.Error("failed to check idempotency key %s of job %s: %v", key, entry.name, err)
+		ec.emitSkipped(entry, "idempotency store unavailable")
+		return "", false
+	}
+	if !claimed {
+		reason := fmt.Sprintf("idempotency key %s already reserved", key)
+		if data, ok, err := ec.store.Get(idempotencyPrefix + key); err == nil && ok {
+			var record idempotencyRecord
+			if json.Unmarshal(data, &record) == nil && record.Completed {
+				reason = fmt.Sprintf("idempotency key %s already completed", key)
+			}
+		}
+		ec.emitSkipped(entry, reason)
+		return "", false
+	}
+	return key, true
+}
+
+// completeIdempotency marks the key of a completed run as completed, and
+// releases the key of any other run so the fire may run again
+func (ec *EnhancedCron) completeIdempotency(key string, metadata *JobMetadata) {
+	if key == "" {
+		return
+	}
+	if metadata == nil || metadata.Status != StatusCompleted {
+		ec.releaseIdempotency(key)
+		return
+	}
+	record := idempotencyRecord{Key: key, RunID: metadata.RunID, Instance: ec.instance, Completed: true, Time: metadata.EndTime}
+	data, err := json.Marshal(record)
+	if err == nil {
+		err = ec.store.Put(idempotencyPrefix+key, data)
+	}
+	if err != nil {
+		ec.logger.Error("failed to record idempotency key %s of job %s: %v", key, metadata.Name, err)
+	}
+}
+
+// releaseIdempotency drops the reservation of a key that did not complete
+func (ec *EnhancedCron) releaseIdempotency(key string) {
+	if err := ec.store.Delete(idempotencyPrefix + key); err != nil {
+		ec.logger.Error("failed to release idempotency key %s: %v", key, err)
+	}
+}
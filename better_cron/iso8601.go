@@ -0,0 +1,153 @@
+package better_cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isoSchedule fires at a fixed anchor plus whole multiples of a period, so
+// runs never drift however long each one takes
+type isoSchedule struct {
+	start   time.Time
+	period  isoPeriod
+	repeats int // occurrences in total; -1 is unbounded
+}
+
+// isoPeriod is an ISO 8601 duration split into calendar and clock parts
+type isoPeriod struct {
+	years, months, days int
+	clock               time.Duration
+}
+
+// isISOInterval reports whether spec looks like an ISO 8601 repeating
+// interval, e.g. R/2025-01-01T00:00:00Z/PT6H or R5/2025-01-01T00:00:00Z/P1D
+func isISOInterval(spec string) bool {
+	return strings.HasPrefix(spec, "R") && strings.Count(spec, "/") == 2
+}
+
+// parseISOInterval parses R[n]/start/period or R[n]/start/end
+func parseISOInterval(spec string) (*isoSchedule, error) {
+	parts := strings.Split(spec, "/")
+	bad := func(format string, args ...interface{}) (*isoSchedule, error) {
+		return nil, fmt.Errorf("invalid interval %q: %s", spec, fmt.Sprintf(format, args...))
+	}
+
+	s := &isoSchedule{repeats: -1}
+	if count := parts[0][1:]; count != "" {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 0 {
+			return bad("invalid repeat count %q", count)
+		}
+		s.repeats = n
+	}
+
+	start, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return bad("start must be an RFC 3339 time: %v", err)
+	}
+	s.start = start
+
+	if strings.HasPrefix(parts[2], "P") {
+		if s.period, err = parseISOPeriod(parts[2]); err != nil {
+			return bad("%v", err)
+		}
+	} else {
+		end, err := time.Parse(time.RFC3339, parts[2])
+		if err != nil {
+			return bad("end must be a duration or an RFC 3339 time: %v", err)
+		}
+		s.period.clock = end.Sub(start)
+	}
+	if s.period.nominal() <= 0 {
+		return bad("period must be positive")
+	}
+	return s, nil
+}
+
+// parseISOPeriod parses PnYnMnDTnHnMnS or PnW
+func parseISOPeriod(s string) (isoPeriod, error) {
+	var p isoPeriod
+	rest := s[1:]
+	if rest == "" {
+		return p, fmt.Errorf("empty duration %q", s)
+	}
+
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime {
+				return p, fmt.Errorf("invalid duration %q", s)
+			}
+			inTime, rest = true, rest[1:]
+			continue
+		}
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if i <= 0 {
+			return p, fmt.Errorf("invalid duration %q", s)
+		}
+		number := strings.Replace(rest[:i], ",", ".", 1)
+		unit := rest[i]
+		rest = rest[i+1:]
+
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return p, fmt.Errorf("invalid duration %q", s)
+		}
+		whole := int(value)
+		fractional := value != float64(whole)
+
+		switch {
+		case !inTime && unit == 'Y' && !fractional:
+			p.years += whole
+		case !inTime && unit == 'M' && !fractional:
+			p.months += whole
+		case !inTime && unit == 'W' && !fractional:
+			p.days += 7 * whole
+		case !inTime && unit == 'D' && !fractional:
+			p.days += whole
+		case inTime && unit == 'H':
+			p.clock += time.Duration(value * float64(time.Hour))
+		case inTime && unit == 'M':
+			p.clock += time.Duration(value * float64(time.Minute))
+		case inTime && unit == 'S':
+			p.clock += time.Duration(value * float64(time.Second))
+		default:
+			return p, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+	return p, nil
+}
+
+// nominal approximates the period's length, for estimating occurrence counts
+func (p isoPeriod) nominal() time.Duration {
+	days := time.Duration(p.years*365+p.months*30+p.days) * 24 * time.Hour
+	return days + p.clock
+}
+
+// at returns the k-th occurrence
+func (s *isoSchedule) at(k int) time.Time {
+	p := s.period
+	return s.start.AddDate(k*p.years, k*p.months, k*p.days).Add(time.Duration(k) * p.clock)
+}
+
+// Next returns the first occurrence after t, or the zero time once the
+// repetitions are used up
+func (s *isoSchedule) Next(t time.Time) time.Time {
+	k := 0
+	// At the start itself the next occurrence is the second one
+	if !t.Before(s.start) {
+		k = int(t.Sub(s.start) / s.period.nominal())
+		for k > 0 && s.at(k-1).After(t) {
+			k--
+		}
+		for !s.at(k).After(t) {
+			k++
+		}
+	}
+	if s.repeats >= 0 && k >= s.repeats {
+		return time.Time{}
+	}
+	return s.at(k)
+}
@@ -0,0 +1,59 @@
+package better_cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestISOIntervalNext(t *testing.T) {
+	from := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		want []time.Time
+	}{
+		// The repetitions run out after the fifth occurrence
+		{"R5/2026-03-10T08:00:00Z/PT1H", []time.Time{at(3, 10, 11), at(3, 10, 12), {}}},
+		{"R2/2026-03-10T00:00:00Z/PT1H", []time.Time{{}}},
+		// At the start itself the next occurrence is the second one
+		{"R/2026-03-10T10:00:00Z/P1D", []time.Time{at(3, 11, 10), at(3, 12, 10), at(3, 13, 10)}},
+		{"R/2026-03-12T00:00:00Z/P1M", []time.Time{at(3, 12, 0), at(4, 12, 0), at(5, 12, 0)}},
+		{"R/2026-01-31T00:00:00Z/P1W", []time.Time{at(3, 14, 0), at(3, 21, 0), at(3, 28, 0)}},
+		{"R/2026-03-10T00:00:00Z/PT1.5H", []time.Time{at(3, 10, 10).Add(30 * time.Minute), at(3, 10, 12), at(3, 10, 13).Add(30 * time.Minute)}},
+		{"R/2026-03-10T00:00:00Z/2026-03-10T06:00:00Z", []time.Time{at(3, 10, 12), at(3, 10, 18), at(3, 11, 0)}},
+	}
+	for _, tt := range tests {
+		schedule, err := parseISOInterval(tt.spec)
+		if err != nil {
+			t.Fatalf("parseISOInterval(%q): %v", tt.spec, err)
+		}
+		next := from
+		for i, want := range tt.want {
+			if next = schedule.Next(next); !next.Equal(want) {
+				t.Errorf("%q fire %d = %v, want %v", tt.spec, i+1, next, want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseISOIntervalErrors(t *testing.T) {
+	for _, spec := range []string{
+		"R/2026-03-10/PT1H",
+		"Rx/2026-03-10T00:00:00Z/PT1H",
+		"R-1/2026-03-10T00:00:00Z/PT1H",
+		"R/2026-03-10T00:00:00Z/P0D",
+		"R/2026-03-10T00:00:00Z/P",
+		"R/2026-03-10T00:00:00Z/P1.5D",
+		"R/2026-03-10T00:00:00Z/PT1D",
+		"R/2026-03-10T00:00:00Z/P1DTT1H",
+		"R/2026-03-10T06:00:00Z/2026-03-10T00:00:00Z",
+	} {
+		if _, err := parseISOInterval(spec); err == nil {
+			t.Errorf("parseISOInterval(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package better_cron
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// isolatedEnv names the job func a helper process was started to run
+const isolatedEnv = "BCRON_ISOLATED_JOB"
+
+// Isolated is a job that runs a func registered with RegisterJobFunc in a
+// helper process: the program re-executes itself, and ServeIsolated runs
+// the func there. A goroutine cannot be killed, but the helper can, so a
+// run that outlives its timeout is ended with SIGKILL. The helper's exit is
+// recorded as a CommandResult.
+type Isolated struct {
+	Func string
+	// MaxOutput caps the bytes kept per output stream; 0 means 1 MiB
+	MaxOutput int
+	// WaitDelay bounds how long to wait for output after the helper is
+	// killed; 0 means 5 seconds
+	WaitDelay time.Duration
+	// Limits constrains the helper's resources
+	Limits *ResourceLimits
+}
+
+// IsolatedJob creates a job running the registered func in a helper process
+func IsolatedJob(funcName string) *Isolated {
+	return &Isolated{Func: funcName}
+}
+
+// Run executes the helper with a background context
+func (j *Isolated) Run() { _, _ = j.RunResult(context.Background()) }
+
+// RunResult starts the helper and returns its CommandResult. A non-zero
+// exit code or a kill is reported as an error.
+func (j *Isolated) RunResult(ctx context.Context) (interface{}, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("isolated job %s: %w", j.Func, err)
+	}
+
+	cmd := &Command{
+		Path:      exe,
+		Env:       append(os.Environ(), isolatedEnv+"="+j.Func),
+		MaxOutput: j.MaxOutput,
+		WaitDelay: j.WaitDelay,
+		Limits:    j.Limits,
+	}
+	result, err := cmd.RunResult(ctx)
+	if err != nil {
+		return result, fmt.Errorf("isolated job %s: %w", j.Func, err)
+	}
+	return result, nil
+}
+
+// ServeIsolated runs the job func of a helper process started by an
+// Isolated job and exits, with status 1 if the job failed. In any other
+// process it returns at once. Call it at the top of main, after the job
+// funcs are registered and before anything else starts.
+func ServeIsolated() {
+	name, ok := os.LookupEnv(isolatedEnv)
+	if !ok {
+		return
+	}
+	os.Unsetenv(isolatedEnv)
+
+	jobFuncs.RLock()
+	job, ok := jobFuncs.jobs[name]
+	jobFuncs.RUnlock()
+	if !ok {
+		fmt.Fprintf(os.Stderr, "no job func registered as %q\n", name)
+		os.Exit(2)
+	}
+
+	if _, err := runJob(context.Background(), job); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func isolatedFactory(def JobDefinition) (cron.Job, error) {
+	jobFuncs.RLock()
+	_, ok := jobFuncs.jobs[def.Func]
+	jobFuncs.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no job func registered as %q", def.Func)
+	}
+	return IsolatedJob(def.Func), nil
+}
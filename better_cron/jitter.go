@@ -0,0 +1,37 @@
+package better_cron
+
+import (
+	"math/rand"
+	"time"
+)
+
+// WithJitter delays every fire of the job by a random duration of up to
+// maxDelay, so identical deployments don't hit shared resources at once
+func WithJitter(maxDelay time.Duration) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.jitter = maxDelay
+	}
+}
+
+// applyJitter sleeps for the job's random splay, and the load smoothing
+// delay if any, and reports false if the scheduler shut down in the meantime
+func (ec *EnhancedCron) applyJitter(entry *jobEntry, f firing) bool {
+	delay := ec.smoothingDelay(entry, f)
+	if entry.cfg.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(entry.cfg.jitter)))
+	}
+	if delay <= 0 {
+		return true
+	}
+
+	timer := ec.clock.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ec.stoppingContext().Done():
+		ec.emitSkipped(entry, "scheduler shutting down")
+		return false
+	case <-timer.C():
+		return true
+	}
+}
@@ -0,0 +1,210 @@
+package better_cron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const journalPrefix = "journal/"
+
+// TriggerRecovery is the trigger of a run that re-runs an interrupted one
+const TriggerRecovery = "recovery"
+
+// errInterrupted is the error of a journaled run that never finished
+var errInterrupted = errors.New("run interrupted: the scheduler stopped before it finished")
+
+// JournalEntry is a run written to the run journal before it started
+type JournalEntry struct {
+	RunID         string    `json:"run_id"`
+	Job           string    `json:"job"`
+	Instance      string    `json:"instance"`
+	Trigger       string    `json:"trigger,omitempty"`
+	ScheduledTime time.Time `json:"scheduled_time"`
+	StartTime     time.Time `json:"start_time"`
+}
+
+// WithRunJournal writes every run to the store before it starts and removes
+// it once the run ends, so the runs a crash cut off are found after a
+// restart. Start reports them with an EventJobInterrupted each and re-runs
+// those of jobs with WithRerunInterrupted; the rest stay listed in
+// InterruptedRuns. A run that cannot be journaled is skipped.
+func WithRunJournal() Option {
+	return func(ec *EnhancedCron) {
+		ec.journal = true
+	}
+}
+
+// WithRerunInterrupted runs the job once more at Start for every run of it
+// that a crash interrupted, when WithRunJournal is set
+func WithRerunInterrupted() JobOption {
+	return func(cfg *jobConfig) {
+		cfg.rerunInterrupted = true
+	}
+}
+
+// journalRun durably records a starting run, reporting false if the run
+// must be skipped because the journal cannot be written; the caller
+// reports the skip
+func (ec *EnhancedCron) journalRun(entry *jobEntry, metadata *JobMetadata) bool {
+	if !ec.journal {
+		return true
+	}
+
+	data, err := json.Marshal(JournalEntry{
+		RunID:         metadata.RunID,
+		Job:           entry.name,
+		Instance:      ec.instance,
+		Trigger:       metadata.Trigger,
+		ScheduledTime: metadata.ScheduledTime,
+		StartTime:     metadata.StartTime,
+	})
+	if err == nil {
+		err = ec.store.Put(journalPrefix+metadata.RunID, data)
+	}
+	if err != nil {
+		ec.logger.Error("failed to journal run of job %s: %v", entry.name, err)
+		return false
+	}
+	return true
+}
+
+// completeJournal removes a run that ended from the journal
+func (ec *EnhancedCron) completeJournal(runID string) {
+	if !ec.journal {
+		return
+	}
+	if err := ec.store.Delete(journalPrefix + runID); err != nil {
+		ec.logger.Error("failed to complete journaled run %s: %v", runID, err)
+	}
+}
+
+// InterruptedRuns returns the journaled runs of earlier scheduler processes
+// that never finished, oldest first
+func (ec *EnhancedCron) InterruptedRuns() ([]JournalEntry, error) {
+	if !ec.journal {
+		return nil, fmt.Errorf("run journal not enabled")
+	}
+
+	keys, err := ec.store.List(journalPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []JournalEntry
+	for _, key := range keys {
+		run, ok, err := ec.loadJournalEntry(strings.TrimPrefix(key, journalPrefix))
+		if err != nil {
+			return nil, err
+		}
+		if ok && run.Instance != ec.instance {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// RerunInterrupted removes an interrupted run from the journal and runs its
+// job again in the background through the full job pipeline
+func (ec *EnhancedCron) RerunInterrupted(runID string) error {
+	if err := ec.writable(); err != nil {
+		return err
+	}
+	run, err := ec.interruptedRun(runID)
+	if err != nil {
+		return err
+	}
+
+	ec.mu.RLock()
+	entry, exists := ec.jobs[run.Job]
+	ec.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("job %q no longer exists", run.Job)
+	}
+
+	if err := ec.store.Delete(journalPrefix + runID); err != nil {
+		return err
+	}
+
+	ec.logger.Info("re-running interrupted run %s of job %s", runID, run.Job)
+	// The re-run covers the slot of the interrupted run
+	go ec.triggerAt(entry.job, entry, TriggerRecovery, run.ScheduledTime)
+	return nil
+}
+
+// DismissInterrupted removes an interrupted run from the journal without
+// running it again
+func (ec *EnhancedCron) DismissInterrupted(runID string) error {
+	if err := ec.writable(); err != nil {
+		return err
+	}
+	if _, err := ec.interruptedRun(runID); err != nil {
+		return err
+	}
+	return ec.store.Delete(journalPrefix + runID)
+}
+
+func (ec *EnhancedCron) interruptedRun(runID string) (JournalEntry, error) {
+	if !ec.journal {
+		return JournalEntry{}, fmt.Errorf("run journal not enabled")
+	}
+	run, ok, err := ec.loadJournalEntry(runID)
+	if err != nil {
+		return run, err
+	}
+	if !ok || run.Instance == ec.instance {
+		return run, fmt.Errorf("interrupted run %q not found", runID)
+	}
+	return run, nil
+}
+
+// recoverJournal reports the runs earlier processes left in the journal and
+// re-runs those of jobs that ask for it. It runs on the first Start only.
+func (ec *EnhancedCron) recoverJournal() {
+	runs, err := ec.InterruptedRuns()
+	if err != nil {
+		ec.logger.Error("failed to read run journal: %v", err)
+		return
+	}
+
+	for _, run := range runs {
+		ec.mu.RLock()
+		entry, exists := ec.jobs[run.Job]
+		ec.mu.RUnlock()
+		if !exists {
+			entry = &jobEntry{name: run.Job, cfg: &jobConfig{}}
+		}
+
+		ec.logger.Error("run %s of job %s was interrupted, started %s", run.RunID, run.Job, run.StartTime.Format(time.RFC3339))
+		ec.emit(EventJobInterrupted, entry, &JobMetadata{
+			ID:            entry.id,
+			RunID:         run.RunID,
+			Name:          run.Job,
+			ScheduledTime: run.ScheduledTime,
+			StartTime:     run.StartTime,
+			Status:        StatusFailed,
+			Error:         errInterrupted,
+			Trigger:       run.Trigger,
+		})
+
+		if exists && entry.cfg.rerunInterrupted {
+			if err := ec.RerunInterrupted(run.RunID); err != nil {
+				ec.logger.Error("failed to re-run interrupted run %s: %v", run.RunID, err)
+			}
+		}
+	}
+}
+
+func (ec *EnhancedCron) loadJournalEntry(runID string) (JournalEntry, bool, error) {
+	var run JournalEntry
+	data, ok, err := ec.store.Get(journalPrefix + runID)
+	if err != nil || !ok {
+		return run, ok, err
+	}
+	if err := json.Unmarshal(data, &run); err != nil {
+		return run, false, fmt.Errorf("decode journaled run %s: %w", runID, err)
+	}
+	return run, true, nil
+}
@@ -0,0 +1,37 @@
+package better_cron
+
+// KafkaProducer is the subset of a Kafka client needed by KafkaSink. It is
+// small enough to adapt from sarama, franz-go or confluent-kafka-go.
+type KafkaProducer interface {
+	Produce(topic string, key, value []byte) error
+}
+
+// KafkaSink is an EventSink that publishes every JobEvent to a Kafka topic,
+// keyed by job name so all events of one job land in the same partition
+type KafkaSink struct {
+	producer KafkaProducer
+	topic    string
+	cfg      sinkConfig
+}
+
+// NewKafkaSink creates a sink publishing to topic through producer
+func NewKafkaSink(producer KafkaProducer, topic string, opts ...SinkOption) *KafkaSink {
+	return &KafkaSink{
+		producer: producer,
+		topic:    topic,
+		cfg:      newSinkConfig(opts),
+	}
+}
+
+// HandleEvent serializes and publishes the event
+func (s *KafkaSink) HandleEvent(event JobEvent) {
+	value, err := s.cfg.serializer(event)
+	if err != nil {
+		s.cfg.logError("kafka: serialize event for job %s: %v", event.Job, err)
+		return
+	}
+
+	if err := s.producer.Produce(s.topic, []byte(event.Job), value); err != nil {
+		s.cfg.logError("kafka: publish event for job %s to %s: %v", event.Job, s.topic, err)
+	}
+}
@@ -0,0 +1,41 @@
+package better_cron
+
+import "fmt"
+
+// ResourceLimits constrains the process of a Command or Isolated job, so a
+// misbehaving job cannot starve the scheduler host. Zero fields are not
+// limited.
+type ResourceLimits struct {
+	// MaxMemory caps memory in bytes: memory.max of the run's cgroup when
+	// Cgroup is set, otherwise the address space rlimit. Go programs
+	// reserve far more address space than they use, so prefer a cgroup
+	// for them.
+	MaxMemory int64
+	// CPUWeight is the cgroup cpu.weight, from 1 to 10000 with 100 as the
+	// default share; it needs Cgroup
+	CPUWeight int
+	// MaxOpenFiles is the rlimit on open file descriptors
+	MaxOpenFiles uint64
+	// Nice is added to the process's niceness, from -20 to 19
+	Nice int
+	// Cgroup is a cgroup v2 directory the scheduler may create a group
+	// per run in, e.g. "/sys/fs/cgroup/bcron"; Linux only
+	Cgroup string
+}
+
+// validate checks the ranges of the limits
+func (l *ResourceLimits) validate() error {
+	if l.MaxMemory < 0 {
+		return fmt.Errorf("max memory must not be negative")
+	}
+	if l.CPUWeight != 0 && (l.CPUWeight < 1 || l.CPUWeight > 10000) {
+		return fmt.Errorf("cpu weight %d is out of range 1-10000", l.CPUWeight)
+	}
+	if l.CPUWeight != 0 && l.Cgroup == "" {
+		return fmt.Errorf("cpu weight needs a cgroup")
+	}
+	if l.Nice < -20 || l.Nice > 19 {
+		return fmt.Errorf("nice %d is out of range -20-19", l.Nice)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package better_cron
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// limitCommand fails where rlimits are not available
+func limitCommand(limits *ResourceLimits, path string, args []string) (string, []string, error) {
+	return "", nil, fmt.Errorf("resource limits are not supported on %s", runtime.GOOS)
+}
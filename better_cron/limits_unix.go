@@ -0,0 +1,34 @@
+//go:build unix
+
+package better_cron
+
+import (
+	"fmt"
+	"strings"
+)
+
+// limitCommand returns the command line that applies the rlimits and
+// niceness before executing path: a shell sets them and then replaces
+// itself with the command, so the exit status is the command's own
+func limitCommand(limits *ResourceLimits, path string, args []string) (string, []string, error) {
+	if err := limits.validate(); err != nil {
+		return "", nil, err
+	}
+
+	var script []string
+	if limits.MaxOpenFiles > 0 {
+		script = append(script, fmt.Sprintf("ulimit -n %d", limits.MaxOpenFiles))
+	}
+	if limits.MaxMemory > 0 && limits.Cgroup == "" {
+		script = append(script, fmt.Sprintf("ulimit -v %d", (limits.MaxMemory+1023)/1024))
+	}
+	exec := `exec "$@"`
+	if limits.Nice != 0 {
+		exec = fmt.Sprintf(`exec nice -n %d "$@"`, limits.Nice)
+	}
+	if len(script) == 0 && limits.Nice == 0 {
+		return path, args, nil
+	}
+	script = append(script, exec)
+	return "/bin/sh", append([]string{"-c", strings.Join(script, " && "), "sh", path}, args...), nil
+}
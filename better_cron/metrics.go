@@ -0,0 +1,19 @@
+package better_cron
+
+// MetricsRecorder receives measurements from the scheduler. Names are dot
+// separated (e.g. "job.queue_length") and tags identify the job or component.
+type MetricsRecorder interface {
+	Gauge(name string, value float64, tags map[string]string)
+}
+
+// WithMetrics sets the recorder that receives scheduler metrics
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(ec *EnhancedCron) {
+		ec.metrics = recorder
+	}
+}
+
+// nopMetrics discards everything; it is used until WithMetrics is given
+type nopMetrics struct{}
+
+func (nopMetrics) Gauge(name string, value float64, tags map[string]string) {}
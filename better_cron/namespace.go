@@ -0,0 +1,223 @@
+package better_cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/robfig/cron/v3"
+)
+
+// namespaceSeparator joins a namespace and a job name into the name the job
+// is registered under
+const namespaceSeparator = "/"
+
+// errNamespaceShutdown is recorded on runs cancelled by Namespace.Shutdown
+var errNamespaceShutdown = errors.New("namespace shut down")
+
+// QualifiedName returns the name a job of namespace is registered under.
+// Jobs of the default namespace "" keep their plain name.
+func QualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + namespaceSeparator + name
+}
+
+// SplitName splits a registered job name into its namespace and the name
+// within it
+func SplitName(qualified string) (namespace, name string) {
+	if i := strings.Index(qualified, namespaceSeparator); i >= 0 {
+		return qualified[:i], qualified[i+1:]
+	}
+	return "", qualified
+}
+
+// WithNamespaceLimit caps how many runs of the namespace's jobs execute at
+// once. Fires beyond the limit wait for a slot, as they do for the rate
+// limiter.
+func WithNamespaceLimit(namespace string, n int) Option {
+	return func(ec *EnhancedCron) {
+		ec.namespaceSlots[namespace] = make(chan struct{}, n)
+	}
+}
+
+// acquireNamespace waits for a run slot of the job's namespace, reporting
+// false if the scheduler begins shutting down first
+func (ec *EnhancedCron) acquireNamespace(entry *jobEntry) (release func(), ok bool) {
+	namespace, _ := SplitName(entry.name)
+	slots := ec.namespaceSlots[namespace]
+	if slots == nil {
+		return func() {}, true
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, true
+	case <-ec.stoppingContext().Done():
+		ec.emitSkipped(entry, "scheduler shutting down")
+		return nil, false
+	}
+}
+
+// retire marks the job so its fires still queued, waiting or deferred are
+// dropped instead of starting
+func (ec *EnhancedCron) retire(entry *jobEntry) {
+	entry.mu.Lock()
+	entry.retired = true
+	entry.mu.Unlock()
+}
+
+// isRetired reports whether the job was removed by Namespace.Shutdown
+func (ec *EnhancedCron) isRetired(entry *jobEntry) bool {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return entry.retired
+}
+
+// admitRun stores a starting run as active unless its job was retired. Both
+// happen under the job's lock, so a Namespace.Shutdown that retired the job
+// finds every run that got past this point.
+func (ec *EnhancedCron) admitRun(entry *jobEntry, run activeJob) bool {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.retired {
+		return false
+	}
+	ec.activeJobs.Store(run.metadata.RunID, run)
+	return true
+}
+
+// Namespace manages the jobs of one namespace of a scheduler. Names given
+// to its methods are relative to the namespace, while the JobInfo and
+// JobMetadata it returns carry qualified names.
+type Namespace struct {
+	ec   *EnhancedCron
+	name string
+}
+
+// Namespace returns the namespace called name; it needs no registration
+func (ec *EnhancedCron) Namespace(name string) *Namespace {
+	return &Namespace{ec: ec, name: name}
+}
+
+// Name returns the name of the namespace
+func (ns *Namespace) Name() string {
+	return ns.name
+}
+
+func (ns *Namespace) check() error {
+	if strings.Contains(ns.name, namespaceSeparator) {
+		return fmt.Errorf("namespace %q must not contain %q", ns.name, namespaceSeparator)
+	}
+	return nil
+}
+
+// AddJob adds a job to the namespace, see EnhancedCron.AddJob
+func (ns *Namespace) AddJob(spec string, job cron.Job, name string, opts ...JobOption) (cron.EntryID, error) {
+	if err := ns.check(); err != nil {
+		return 0, err
+	}
+	return ns.ec.AddJob(spec, job, QualifiedName(ns.name, name), opts...)
+}
+
+// UpdateJob replaces a job of the namespace, see EnhancedCron.UpdateJob
+func (ns *Namespace) UpdateJob(spec string, job cron.Job, name string, opts ...JobOption) (cron.EntryID, error) {
+	if err := ns.check(); err != nil {
+		return 0, err
+	}
+	return ns.ec.UpdateJob(spec, job, QualifiedName(ns.name, name), opts...)
+}
+
+// RemoveJob unregisters a job of the namespace
+func (ns *Namespace) RemoveJob(name string) error {
+	return ns.ec.RemoveJob(QualifiedName(ns.name, name))
+}
+
+// owns reports whether a qualified job name belongs to the namespace
+func (ns *Namespace) owns(qualified string) bool {
+	namespace, _ := SplitName(qualified)
+	return namespace == ns.name
+}
+
+// ListJobs returns the namespace's jobs sorted by name
+func (ns *Namespace) ListJobs() []JobInfo {
+	var jobs []JobInfo
+	for _, job := range ns.ec.ListJobs() {
+		if job.Namespace == ns.name {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// GetActiveJobs returns the runs of the namespace's jobs in flight
+func (ns *Namespace) GetActiveJobs() []*JobMetadata {
+	var runs []*JobMetadata
+	for _, run := range ns.ec.GetActiveJobs() {
+		if ns.owns(run.Name) {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// Shutdown removes every job of the namespace and waits for their runs in
+// flight, leaving other namespaces running. Fires that have yet to start,
+// queued or deferred, are dropped. ShutdownAbort cancels the runs at once;
+// under ShutdownDrain they are cancelled when ctx expires. The namespace
+// can be filled again afterwards.
+func (ns *Namespace) Shutdown(ctx context.Context, mode ShutdownMode) error {
+	var entries []*jobEntry
+	ns.ec.mu.RLock()
+	for name, entry := range ns.ec.jobs {
+		if ns.owns(name) {
+			entries = append(entries, entry)
+		}
+	}
+	ns.ec.mu.RUnlock()
+	for _, entry := range entries {
+		ns.ec.retire(entry)
+		if err := ns.ec.RemoveJob(entry.name); err != nil {
+			ns.ec.logger.Error("namespace %s: %v", ns.name, err)
+		}
+	}
+
+	var runs []activeJob
+	ns.ec.activeJobs.Range(func(key, value interface{}) bool {
+		if run := value.(activeJob); ns.owns(run.metadata.Name) {
+			runs = append(runs, run)
+		}
+		return true
+	})
+
+	var wg sync.WaitGroup
+	for _, run := range runs {
+		if mode == ShutdownAbort {
+			run.cancel(errNamespaceShutdown)
+		}
+		wg.Add(1)
+		go func(jobWg *sync.WaitGroup) {
+			defer wg.Done()
+			jobWg.Wait()
+		}(run.wg)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	ns.ec.logger.Info("shutting down namespace %s, %d run(s) in flight", ns.name, len(runs))
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		for _, run := range runs {
+			run.cancel(errNamespaceShutdown)
+		}
+		return fmt.Errorf("shutdown of namespace %s did not complete: %w", ns.name, ctx.Err())
+	}
+}
@@ -0,0 +1,41 @@
+package better_cron_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"cron_test/bcrontest"
+	"cron_test/better_cron"
+)
+
+func TestNamespaceShutdownDropsQueuedFires(t *testing.T) {
+	skips := &skipRecorder{}
+	r := bcrontest.New(t, epoch, better_cron.WithEventSink(better_cron.EventSinkFunc(skips.handle)))
+	defer r.Close()
+
+	ns := r.Cron.Namespace("team")
+	if _, err := ns.AddJob("@every 1m", sleepJob(r.Clock, 90*time.Second), "slow",
+		better_cron.WithQueue(1, better_cron.DropNewest)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	// The fire at 00:02 is queued behind the run of 00:01
+	r.Advance(2*time.Minute + 15*time.Second)
+	if err := ns.Shutdown(context.Background(), better_cron.ShutdownAbort); err != nil {
+		t.Fatal(err)
+	}
+	r.Advance(5 * time.Minute)
+
+	runs := r.RunsOf("team/slow")
+	if len(runs) != 1 || runs[0].Status != better_cron.StatusCancelled {
+		t.Fatalf("runs after shutdown: %+v, want the cancelled first run only", runs)
+	}
+	skips.mu.Lock()
+	defer skips.mu.Unlock()
+	if !slices.Contains(skips.reasons, "namespace shut down") {
+		t.Errorf("skip reasons %q, want the queued fire dropped by the shutdown", skips.reasons)
+	}
+}
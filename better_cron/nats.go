@@ -0,0 +1,62 @@
+package better_cron
+
+import (
+	"strings"
+)
+
+// NATSPublisher is the subset of a NATS connection needed by NATSSink.
+// *nats.Conn satisfies it directly.
+type NATSPublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// SubjectFunc chooses the NATS subject an event is published on
+type SubjectFunc func(event JobEvent) string
+
+// SubjectPerEventType publishes on "<prefix>.<event type>", e.g. "bcron.failed"
+func SubjectPerEventType(prefix string) SubjectFunc {
+	return func(event JobEvent) string {
+		return prefix + "." + event.Type.String()
+	}
+}
+
+// SubjectPerJob publishes on "<prefix>.<job>.<event type>", so subscribers can
+// watch a single job with "<prefix>.<job>.>"
+func SubjectPerJob(prefix string) SubjectFunc {
+	return func(event JobEvent) string {
+		return prefix + "." + subjectToken(event.Job) + "." + event.Type.String()
+	}
+}
+
+// subjectToken replaces characters that have special meaning in NATS subjects
+var subjectToken = strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_").Replace
+
+// NATSSink is an EventSink that publishes every JobEvent to NATS
+type NATSSink struct {
+	conn    NATSPublisher
+	subject SubjectFunc
+	cfg     sinkConfig
+}
+
+// NewNATSSink creates a sink publishing through conn on subjects chosen by subject
+func NewNATSSink(conn NATSPublisher, subject SubjectFunc, opts ...SinkOption) *NATSSink {
+	return &NATSSink{
+		conn:    conn,
+		subject: subject,
+		cfg:     newSinkConfig(opts),
+	}
+}
+
+// HandleEvent serializes and publishes the event
+func (s *NATSSink) HandleEvent(event JobEvent) {
+	data, err := s.cfg.serializer(event)
+	if err != nil {
+		s.cfg.logError("nats: serialize event for job %s: %v", event.Job, err)
+		return
+	}
+
+	subject := s.subject(event)
+	if err := s.conn.Publish(subject, data); err != nil {
+		s.cfg.logError("nats: publish event for job %s to %s: %v", event.Job, subject, err)
+	}
+}
@@ -0,0 +1,173 @@
+package better_cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// isNaturalSpec reports whether spec is written in the human-readable form,
+// e.g. "every 5 minutes" or "every Monday at noon"
+func isNaturalSpec(spec string) bool {
+	fields := strings.Fields(spec)
+	return len(fields) > 0 && strings.ToLower(fields[0]) == "every"
+}
+
+var naturalUnits = map[string]struct {
+	field int // index of the cron field the unit steps
+	span  int // values in that field
+	short string
+}{
+	"second": {0, 60, "s"},
+	"minute": {1, 60, "m"},
+	"hour":   {2, 24, "h"},
+}
+
+var naturalDays = map[string]string{
+	"sunday": "0", "monday": "1", "tuesday": "2", "wednesday": "3",
+	"thursday": "4", "friday": "5", "saturday": "6",
+	"weekday": "1-5", "weekend": "0,6",
+}
+
+// translateNatural converts a human-readable schedule to a six-field cron
+// spec. Supported forms:
+//
+//	every [N] second(s)|minute(s)|hour(s)
+//	every day [at TIME]
+//	every weekday|weekend [at TIME]
+//	every monday[, wednesday and friday] [at TIME]
+//	every month on the Nth [at TIME]
+//
+// TIME is HH:MM, HH:MM:SS, 9am, 9:30pm, noon or midnight and defaults to
+// midnight.
+func translateNatural(spec string) (string, error) {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(spec, ",", " ")))[1:]
+	bad := func(format string, args ...interface{}) (string, error) {
+		return "", fmt.Errorf("invalid schedule %q: %s", spec, fmt.Sprintf(format, args...))
+	}
+	if len(words) == 0 {
+		return bad("expected a unit or day after \"every\"")
+	}
+
+	// every [N] unit(s)
+	n := 1
+	if v, err := strconv.Atoi(words[0]); err == nil {
+		if v < 1 {
+			return bad("interval must be positive")
+		}
+		n, words = v, words[1:]
+		if len(words) == 0 {
+			return bad("expected a unit after %d", n)
+		}
+	}
+	if unit, ok := naturalUnits[strings.TrimSuffix(words[0], "s")]; ok {
+		if len(words) > 1 {
+			return bad("unexpected %q after the interval", strings.Join(words[1:], " "))
+		}
+		if unit.span%n != 0 {
+			// Steps that don't divide the field evenly would bunch up at the
+			// wrap, so fall back to a fixed delay
+			return "@every " + strconv.Itoa(n) + unit.short, nil
+		}
+		fields := []string{"0", "0", "0", "*", "*", "*"}
+		for i := unit.field + 1; i < 3; i++ {
+			fields[i] = "*"
+		}
+		fields[unit.field] = "*"
+		if n > 1 {
+			fields[unit.field] = "*/" + strconv.Itoa(n)
+		}
+		return strings.Join(fields, " "), nil
+	}
+	if n != 1 {
+		return bad("unknown unit %q", words[0])
+	}
+
+	// Split off the time of day
+	clock := "midnight"
+	for i, w := range words {
+		if w == "at" {
+			if i != len(words)-2 {
+				return bad("expected a single time after \"at\"")
+			}
+			clock, words = words[i+1], words[:i]
+			break
+		}
+	}
+	hour, minute, second, err := parseNaturalTime(clock)
+	if err != nil {
+		return bad("%v", err)
+	}
+	timeFields := fmt.Sprintf("%d %d %d", second, minute, hour)
+
+	switch {
+	case len(words) == 1 && words[0] == "day":
+		return timeFields + " * * *", nil
+	case len(words) >= 1 && words[0] == "month":
+		if len(words) != 4 || words[1] != "on" || words[2] != "the" {
+			return bad("expected \"every month on the Nth\"")
+		}
+		day, err := strconv.Atoi(strings.TrimRight(words[3], "stndrh"))
+		if err != nil || day < 1 || day > 31 {
+			return bad("invalid day of month %q", words[3])
+		}
+		return fmt.Sprintf("%s %d * *", timeFields, day), nil
+	}
+
+	var days []string
+	for _, w := range words {
+		if w == "and" {
+			continue
+		}
+		day, ok := naturalDays[w]
+		if !ok {
+			return bad("unknown day %q", w)
+		}
+		days = append(days, day)
+	}
+	if len(days) == 0 {
+		return bad("expected a day")
+	}
+	return timeFields + " * * " + strings.Join(days, ","), nil
+}
+
+// parseNaturalTime parses a time of day such as 09:30, 9am, 9:30pm or noon
+func parseNaturalTime(s string) (hour, minute, second int, err error) {
+	switch s {
+	case "noon":
+		return 12, 0, 0, nil
+	case "midnight":
+		return 0, 0, 0, nil
+	}
+
+	meridiem := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		meridiem, s = s[len(s)-2:], s[:len(s)-2]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 || (meridiem == "" && len(parts) < 2) {
+		return 0, 0, 0, fmt.Errorf("invalid time %q", s+meridiem)
+	}
+	values := make([]int, 3)
+	for i, p := range parts {
+		if values[i], err = strconv.Atoi(p); err != nil || values[i] < 0 {
+			return 0, 0, 0, fmt.Errorf("invalid time %q", s+meridiem)
+		}
+	}
+	hour, minute, second = values[0], values[1], values[2]
+
+	if meridiem != "" {
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0, fmt.Errorf("invalid time %q", s+meridiem)
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return 0, 0, 0, fmt.Errorf("invalid time %q", s+meridiem)
+	}
+	return hour, minute, second, nil
+}
@@ -0,0 +1,86 @@
+package better_cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTranslateNatural(t *testing.T) {
+	tests := []struct {
+		spec, want string
+	}{
+		{"every second", "* * * * * *"},
+		{"every minute", "0 * * * * *"},
+		{"every 5 minutes", "0 */5 * * * *"},
+		{"every 2 hours", "0 0 */2 * * *"},
+		{"every 7 seconds", "@every 7s"},
+		{"every day", "0 0 0 * * *"},
+		{"Every Day At 06:15:30", "30 15 6 * * *"},
+		{"every monday, wednesday and friday at 18:45", "0 45 18 * * 1,3,5"},
+		{"every weekend at noon", "0 0 12 * * 0,6"},
+		{"every weekday at midnight", "0 0 0 * * 1-5"},
+		{"every month on the 15th at 9pm", "0 0 21 15 * *"},
+		{"every sunday at 12am", "0 0 0 * * 0"},
+	}
+	for _, tt := range tests {
+		got, err := translateNatural(tt.spec)
+		if err != nil {
+			t.Errorf("translateNatural(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("translateNatural(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestTranslateNaturalErrors(t *testing.T) {
+	for _, spec := range []string{
+		"every",
+		"every 0 minutes",
+		"every 5",
+		"every 2 fortnights",
+		"every fortnight",
+		"every 5 minutes at noon",
+		"every day at 25:00",
+		"every day at 13pm",
+		"every day at noon and midnight",
+		"every month on the 32nd",
+		"every month",
+	} {
+		if got, err := translateNatural(spec); err == nil {
+			t.Errorf("translateNatural(%q) = %q, want an error", spec, got)
+		}
+	}
+}
+
+func TestNaturalScheduleNext(t *testing.T) {
+	// Tuesday
+	from := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		want []time.Time
+	}{
+		{"every weekday at 9:30am", []time.Time{at(3, 11, 9, 30), at(3, 12, 9, 30), at(3, 13, 9, 30)}},
+		{"every 15 minutes", []time.Time{at(3, 10, 10, 15), at(3, 10, 10, 30), at(3, 10, 10, 45)}},
+		{"every 7 minutes", []time.Time{at(3, 10, 10, 7), at(3, 10, 10, 14), at(3, 10, 10, 21)}},
+		{"every month on the 1st at noon", []time.Time{at(4, 1, 12, 0), at(5, 1, 12, 0), at(6, 1, 12, 0)}},
+	}
+	for _, tt := range tests {
+		schedule, err := parseSchedule(tt.spec, nil)
+		if err != nil {
+			t.Fatalf("parseSchedule(%q): %v", tt.spec, err)
+		}
+		next := from
+		for i, want := range tt.want {
+			if next = schedule.Next(next); !next.Equal(want) {
+				t.Errorf("%q fire %d = %v, want %v", tt.spec, i+1, next, want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package better_cron
+
+import (
+	"github.com/robfig/cron/v3"
+)
+
+// OverlapPolicy decides what happens when a job fires while a previous run
+// of the same job is still active
+type OverlapPolicy int
+
+const (
+	// OverlapAllow runs the new fire concurrently with the previous run
+	OverlapAllow OverlapPolicy = iota
+	// OverlapSkip drops the new fire and emits EventJobSkipped
+	OverlapSkip
+	// OverlapQueue holds the new fire until the previous run finishes
+	OverlapQueue
+)
+
+// OverflowPolicy decides which fire is dropped when a job's queue is full
+type OverflowPolicy int
+
+const (
+	// DropNewest discards the fire that didn't fit in the queue
+	DropNewest OverflowPolicy = iota
+	// DropOldest discards the longest waiting fire to make room
+	DropOldest
+)
+
+// WithOverlapPolicy sets how a job handles fires that overlap a running run
+func WithOverlapPolicy(policy OverlapPolicy) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.overlap = policy
+	}
+}
+
+// WithQueue makes overlapping fires wait in a queue of at most depth fires,
+// which run one after another once the current run finishes
+func WithQueue(depth int, overflow OverflowPolicy) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.overlap = OverlapQueue
+		cfg.queueDepth = depth
+		cfg.overflow = overflow
+	}
+}
+
+// WithMaxInstances limits how many runs of the job may execute at once.
+// Fires beyond the limit are skipped, or queued when combined with WithQueue.
+func WithMaxInstances(n int) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.maxInstances = n
+	}
+}
+
+// instanceLimit returns how many runs of the job may overlap, or 0 for no limit
+func (cfg *jobConfig) instanceLimit() int {
+	if cfg.maxInstances > 0 {
+		return cfg.maxInstances
+	}
+	if cfg.overlap == OverlapAllow {
+		return 0
+	}
+	return 1
+}
+
+// acquireOverlap registers a starting run of the job, or reports false if
+// the fire has to be skipped or queued because of the overlap policy
+func (ec *EnhancedCron) acquireOverlap(entry *jobEntry, f firing) bool {
+	acquired, skipReason := ec.tryAcquireOverlap(entry, f)
+	if skipReason != "" {
+		ec.emitSkipped(entry, skipReason)
+	}
+	return acquired
+}
+
+// tryAcquireOverlap applies the overlap policy under the entry lock and
+// returns the reason a fire was dropped, if any
+func (ec *EnhancedCron) tryAcquireOverlap(entry *jobEntry, f firing) (bool, string) {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	limit := entry.cfg.instanceLimit()
+	if limit == 0 || entry.running < limit {
+		entry.running++
+		return true, ""
+	}
+
+	if entry.cfg.overlap != OverlapQueue {
+		if limit > 1 {
+			return false, "max instances reached"
+		}
+		return false, "previous run still active"
+	}
+
+	var skipReason string
+	if len(entry.queue) >= entry.cfg.queueDepth {
+		if entry.cfg.overflow == DropNewest || len(entry.queue) == 0 {
+			return false, "overlap queue full"
+		}
+		entry.queue = entry.queue[1:]
+		skipReason = "dropped oldest queued fire"
+	}
+	entry.queue = append(entry.queue, f)
+	ec.recordQueueLength(entry)
+	return false, skipReason
+}
+
+// releaseOverlap unregisters a finished run of the job, handing its slot to
+// the next queued fire if there is one
+func (ec *EnhancedCron) releaseOverlap(job cron.Job, entry *jobEntry) {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if ec.stoppingContext().Err() != nil {
+		entry.queue = nil
+	}
+
+	if len(entry.queue) > 0 {
+		next := entry.queue[0]
+		entry.queue = entry.queue[1:]
+		ec.recordQueueLength(entry)
+		go ec.submit(job, entry, next)
+		return
+	}
+	entry.running--
+}
+
+// recordQueueLength publishes and logs the queue depth; entry.mu must be held
+func (ec *EnhancedCron) recordQueueLength(entry *jobEntry) {
+	ec.debug("queue", "job", entry.name, "depth", len(entry.queue))
+	ec.metrics.Gauge("job.queue_length", float64(len(entry.queue)), map[string]string{"job": entry.name})
+}
+
+// QueueLength returns the number of fires waiting for a job's current run
+func (ec *EnhancedCron) QueueLength(name string) int {
+	ec.mu.RLock()
+	entry, ok := ec.jobs[name]
+	ec.mu.RUnlock()
+	if !ok {
+		return 0
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return len(entry.queue)
+}
@@ -0,0 +1,124 @@
+package better_cron
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// TightPolicy decides what happens to fires of a job whose runs take about
+// as long as its schedule's interval
+type TightPolicy int
+
+const (
+	// TightWarn only reports the tight schedule
+	TightWarn TightPolicy = iota
+	// TightSkip skips fires while a run of the job is still going
+	TightSkip
+	// TightDelay spaces fires at least the average run duration apart
+	TightDelay
+)
+
+// WithTightSchedule watches how long the job's runs take on average against
+// its schedule's interval. Once the average reaches ratio times the
+// interval, e.g. 0.8, the schedule is too tight: an EventScheduleTight is
+// emitted and policy decides what happens to the fires until the average
+// drops below again, rather than letting runs pile up.
+func WithTightSchedule(ratio float64, policy TightPolicy) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.tightRatio = ratio
+		cfg.tightPolicy = policy
+	}
+}
+
+// paceSchedule skips fires that would come sooner than a gap after t
+type paceSchedule struct {
+	cron.Schedule
+	gap *atomic.Int64
+}
+
+// Next returns the first fire of the schedule at least the gap after t
+func (s paceSchedule) Next(t time.Time) time.Time {
+	next := s.Schedule.Next(t)
+	gap := time.Duration(s.gap.Load())
+	for i := 0; i < maxPaceSkips && !next.IsZero() && next.Sub(t) < gap; i++ {
+		next = s.Schedule.Next(next)
+	}
+	return next
+}
+
+// maxPaceSkips bounds the fires paceSchedule skips at once
+const maxPaceSkips = 1 << 16
+
+// checkPace skips a fire of a job on a tight schedule with TightSkip while
+// one of its runs is still going
+func (ec *EnhancedCron) checkPace(entry *jobEntry) bool {
+	if entry.cfg.tightRatio <= 0 || entry.cfg.tightPolicy != TightSkip {
+		return true
+	}
+	entry.mu.Lock()
+	busy := entry.tight && entry.running > 0
+	entry.mu.Unlock()
+	if busy {
+		ec.emitSkipped(entry, "schedule too tight")
+	}
+	return !busy
+}
+
+// recordPace folds a finished run into the job's average duration and
+// reports when its schedule becomes too tight or stops being so
+func (ec *EnhancedCron) recordPace(entry *jobEntry, metadata *JobMetadata) {
+	if entry.cfg.tightRatio <= 0 || metadata.Status == StatusCancelled {
+		return
+	}
+	duration := metadata.EndTime.Sub(metadata.StartTime)
+
+	now := ec.clock.Now()
+	first := entry.schedule.Next(now)
+	interval := entry.schedule.Next(first).Sub(first)
+	if first.IsZero() || interval <= 0 {
+		return
+	}
+
+	entry.mu.Lock()
+	if entry.avgDuration == 0 {
+		entry.avgDuration = duration
+	} else {
+		entry.avgDuration = (entry.avgDuration*7 + duration*3) / 10
+	}
+	avg := entry.avgDuration
+	tight := float64(avg) >= entry.cfg.tightRatio*float64(interval)
+	changed := tight != entry.tight
+	entry.tight = tight
+	entry.mu.Unlock()
+
+	if entry.cfg.tightPolicy == TightDelay {
+		gap := time.Duration(0)
+		if tight {
+			gap = avg
+		}
+		if entry.paceGap.Swap(int64(gap)) != int64(gap) {
+			ec.rescheduleJob(entry)
+		}
+	}
+
+	if !changed {
+		return
+	}
+	if tight {
+		reason := fmt.Sprintf("runs take %v on average, the interval is %v", avg.Round(time.Millisecond), interval)
+		ec.logger.Error("schedule of job %s is too tight: %s", entry.name, reason)
+		ec.dispatch(entry, JobEvent{
+			Type:     EventScheduleTight,
+			Job:      entry.name,
+			Tags:     entry.cfg.tags,
+			Time:     now,
+			Metadata: *metadata,
+			Reason:   reason,
+		})
+	} else {
+		ec.logger.Info("schedule of job %s is no longer too tight", entry.name)
+	}
+}
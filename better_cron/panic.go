@@ -0,0 +1,42 @@
+package better_cron
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PanicHandler is called with the run metadata, the recovered value and the
+// stack trace of the goroutine that panicked
+type PanicHandler func(meta *JobMetadata, recovered interface{}, stack []byte)
+
+// WithPanicHandler sets a handler called whenever a job attempt panics
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(ec *EnhancedCron) {
+		ec.panicHandler = handler
+	}
+}
+
+// PanicError is the error recorded for an attempt that panicked
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("job panic: %v", e.Value)
+}
+
+// handlePanic records the stack of a panicked attempt and hands it to the
+// configured panic handler
+func (ec *EnhancedCron) handlePanic(metadata *JobMetadata, err error) {
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		return
+	}
+
+	metadata.Stack = pe.Stack
+	ec.logger.Error("job %s panicked: %v\n%s", metadata.Name, pe.Value, pe.Stack)
+	if ec.panicHandler != nil {
+		ec.panicHandler(metadata, pe.Value, pe.Stack)
+	}
+}
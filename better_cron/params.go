@@ -0,0 +1,42 @@
+package better_cron
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// WithParams sets parameters delivered to every run of the job through
+// ParamsFromContext and recorded in its history, so one job implementation
+// can be registered several times with different configurations. Params of
+// repeated WithParams options are merged. Values containing "{{" are
+// templates expanded for every run against its TemplateData.
+func WithParams(params map[string]string) JobOption {
+	return func(cfg *jobConfig) {
+		merged := make(map[string]string, len(cfg.params)+len(params))
+		for k, v := range cfg.params {
+			merged[k] = v
+		}
+		for k, v := range params {
+			merged[k] = v
+		}
+		cfg.params = merged
+	}
+}
+
+// ParamsFromContext returns the parameters of the current run's job, or nil
+// if ctx does not belong to a run. The map must not be modified.
+func ParamsFromContext(ctx context.Context) map[string]string {
+	id, _ := ctx.Value(identityKey).(runIdentity)
+	return id.params
+}
+
+// joinParams formats params as sorted key=value pairs
+func joinParams(params map[string]string) string {
+	pairs := make([]string, 0, len(params))
+	for k, v := range params {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,134 @@
+package better_cron
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActorFailureThreshold is the actor of jobs paused by WithFailureThreshold
+const ActorFailureThreshold = "failure_threshold"
+
+// WithFailureThreshold pauses the job once n runs in a row have failed, so a
+// broken job stops running every cycle until someone looks at it. The pause
+// is reported with an EventJobPaused to every sink and the job's notifiers;
+// ResumeJob starts the count over. Cancelled runs neither count nor reset it.
+func WithFailureThreshold(n int) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.failureThreshold = n
+	}
+}
+
+// PauseJob stops the job from firing on its schedule until ResumeJob is
+// called. Runs in flight finish normally and TriggerJob still runs it. The
+// pause survives UpdateJob.
+func (ec *EnhancedCron) PauseJob(name string) error {
+	return ec.pauseJob(name, ActorAPI, "paused")
+}
+
+// ResumeJob lets a paused job fire on its schedule again
+func (ec *EnhancedCron) ResumeJob(name string) error {
+	return ec.resumeJob(name, ActorAPI)
+}
+
+// pauseJob pauses a job on behalf of actor
+func (ec *EnhancedCron) pauseJob(name, actor, reason string) error {
+	entry, err := ec.pausable(name)
+	if err != nil {
+		return err
+	}
+	entry.mu.Lock()
+	changed := !entry.paused
+	entry.paused = true
+	entry.mu.Unlock()
+
+	if changed {
+		ec.logger.Info("job %s paused by %s: %s", name, actor, reason)
+		ec.emitPause(EventJobPaused, entry, actor, reason)
+	}
+	return nil
+}
+
+// resumeJob resumes a job on behalf of actor
+func (ec *EnhancedCron) resumeJob(name, actor string) error {
+	entry, err := ec.pausable(name)
+	if err != nil {
+		return err
+	}
+	entry.mu.Lock()
+	changed := entry.paused
+	entry.paused = false
+	entry.failStreak = 0
+	entry.mu.Unlock()
+
+	ec.recordBackoff(entry, 0)
+	if changed {
+		ec.logger.Info("job %s resumed by %s", name, actor)
+		ec.emitPause(EventJobResumed, entry, actor, "")
+	}
+	return nil
+}
+
+func (ec *EnhancedCron) pausable(name string) (*jobEntry, error) {
+	if err := ec.writable(); err != nil {
+		return nil, err
+	}
+	ec.mu.RLock()
+	entry, ok := ec.jobs[name]
+	ec.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("job %q not found", name)
+	}
+	return entry, nil
+}
+
+// emitPause reports a pause or resume, with the actor as the trigger
+func (ec *EnhancedCron) emitPause(eventType EventType, entry *jobEntry, actor, reason string) {
+	ec.dispatch(entry, JobEvent{
+		Type:     eventType,
+		Job:      entry.name,
+		Tags:     entry.cfg.tags,
+		Time:     ec.clock.Now(),
+		Metadata: JobMetadata{ID: entry.id, Name: entry.name, Status: StatusIdle, Trigger: actor},
+		Reason:   reason,
+	})
+}
+
+// isPaused reports whether the job is paused
+func (entry *jobEntry) isPaused() bool {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return entry.paused
+}
+
+// manualTrigger reports whether a trigger was asked for explicitly, so it
+// runs even while the job is paused
+func manualTrigger(trigger string) bool {
+	return trigger == TriggerManual || strings.HasPrefix(trigger, "admin:")
+}
+
+// recordFailureStreak counts consecutive failed runs, backing the schedule
+// off and pausing the job once they reach its failure threshold
+func (ec *EnhancedCron) recordFailureStreak(entry *jobEntry, metadata *JobMetadata) {
+	threshold := entry.cfg.failureThreshold
+	if threshold <= 0 && entry.cfg.backoffMax <= 0 {
+		return
+	}
+
+	entry.mu.Lock()
+	switch metadata.Status {
+	case StatusCompleted:
+		entry.failStreak = 0
+	case StatusFailed:
+		entry.failStreak++
+	}
+	streak := entry.failStreak
+	entry.mu.Unlock()
+
+	ec.recordBackoff(entry, streak)
+	if threshold > 0 && metadata.Status == StatusFailed && streak >= threshold && !entry.isPaused() {
+		ec.logger.Error("job %s failed %d times in a row, pausing it: %v", entry.name, streak, metadata.Error)
+		if err := ec.pauseJob(entry.name, ActorFailureThreshold, fmt.Sprintf("%d consecutive failures", streak)); err != nil {
+			ec.logger.Error("failed to pause job %s: %v", entry.name, err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package better_cron_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cron_test/bcrontest"
+	"cron_test/better_cron"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// skipRecorder counts the skipped events of every job
+type skipRecorder struct {
+	mu      sync.Mutex
+	reasons []string
+}
+
+func (s *skipRecorder) handle(event better_cron.JobEvent) {
+	if event.Type != better_cron.EventJobSkipped {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reasons = append(s.reasons, event.Reason)
+}
+
+func (s *skipRecorder) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.reasons)
+}
+
+// sleepJob runs for d of virtual time
+func sleepJob(clock better_cron.Clock, d time.Duration) better_cron.ErrorFuncJob {
+	return func(ctx context.Context) error {
+		timer := clock.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func TestPipelineCircuitBreaker(t *testing.T) {
+	r := bcrontest.New(t, epoch)
+	defer r.Close()
+
+	var healthy atomic.Bool
+	job := better_cron.ErrorFuncJob(func(context.Context) error {
+		if healthy.Load() {
+			return nil
+		}
+		return errors.New("down")
+	})
+	if _, err := r.Cron.AddJob("@every 1m", job, "flaky", better_cron.WithCircuitBreaker(2, 5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	// Two failures open the breaker, which then skips fires until the
+	// cooldown ends at 00:07 and the probe fails again
+	r.Advance(8 * time.Minute)
+	r.ExpectRuns(t, "flaky", 3, epoch, epoch.Add(8*time.Minute))
+	r.ExpectStatus(t, "flaky", better_cron.StatusFailed)
+	if state, _ := r.Cron.GetBreakerState("flaky"); state != better_cron.BreakerOpen {
+		t.Errorf("breaker is %s after a failed probe, want open", state)
+	}
+
+	// The probe at 00:12 succeeds and closes it
+	healthy.Store(true)
+	r.Advance(6 * time.Minute)
+	r.ExpectRuns(t, "flaky", 3, epoch.Add(8*time.Minute+time.Second), epoch.Add(14*time.Minute))
+	if state, _ := r.Cron.GetBreakerState("flaky"); state != better_cron.BreakerClosed {
+		t.Errorf("breaker is %s after a successful probe, want closed", state)
+	}
+}
+
+func TestPipelineOverlapSkip(t *testing.T) {
+	skips := &skipRecorder{}
+	r := bcrontest.New(t, epoch, better_cron.WithEventSink(better_cron.EventSinkFunc(skips.handle)))
+	defer r.Close()
+
+	if _, err := r.Cron.AddJob("@every 1m", sleepJob(r.Clock, 90*time.Second), "slow",
+		better_cron.WithOverlapPolicy(better_cron.OverlapSkip)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	// Runs start at 00:01 and 00:03; the fires at 00:02 and 00:04 land
+	// while a run is active
+	r.Advance(4*time.Minute + 30*time.Second)
+	r.ExpectRuns(t, "slow", 2, epoch, epoch.Add(5*time.Minute))
+	r.ExpectStatus(t, "slow", better_cron.StatusCompleted)
+	if n := skips.count(); n != 2 {
+		t.Errorf("%d fires skipped, want 2", n)
+	}
+}
+
+func TestPipelineOverlapQueue(t *testing.T) {
+	r := bcrontest.New(t, epoch)
+	defer r.Close()
+
+	if _, err := r.Cron.AddJob("@every 1m", sleepJob(r.Clock, 90*time.Second), "slow",
+		better_cron.WithQueue(1, better_cron.DropNewest)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	// The fire at 00:02 waits for the first run and starts when it ends
+	r.Advance(2*time.Minute + 30*time.Second)
+	runs := r.RunsOf("slow")
+	if len(runs) != 1 {
+		t.Fatalf("%d runs finished, want 1", len(runs))
+	}
+	r.Advance(90 * time.Second)
+	runs = r.RunsOf("slow")
+	if len(runs) != 2 {
+		t.Fatalf("%d runs finished, want 2", len(runs))
+	}
+	if !runs[1].Start.Equal(runs[0].End) {
+		t.Errorf("queued run started at %v, want when the first ended at %v", runs[1].Start, runs[0].End)
+	}
+}
+
+func TestPipelineRetry(t *testing.T) {
+	r := bcrontest.New(t, epoch)
+	defer r.Close()
+
+	var calls atomic.Int32
+	job := better_cron.ErrorFuncJob(func(context.Context) error {
+		if calls.Add(1) < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	backoff := func(int) time.Duration { return 10 * time.Second }
+	if _, err := r.Cron.AddJob("@every 1h", job, "retrying", better_cron.WithRetry(3, backoff)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	r.Advance(time.Hour + time.Minute)
+	runs := r.RunsOf("retrying")
+	if len(runs) != 1 {
+		t.Fatalf("%d runs recorded, want 1", len(runs))
+	}
+	if runs[0].Status != better_cron.StatusCompleted || runs[0].Attempt != 3 {
+		t.Errorf("run ended %s on attempt %d, want completed on attempt 3", runs[0].Status, runs[0].Attempt)
+	}
+	if got := runs[0].End.Sub(runs[0].Start); got < 20*time.Second {
+		t.Errorf("run took %v, want at least the 20s of backoff", got)
+	}
+}
+
+func TestPipelineRetryExhausted(t *testing.T) {
+	r := bcrontest.New(t, epoch)
+	defer r.Close()
+
+	var calls atomic.Int32
+	job := better_cron.ErrorFuncJob(func(context.Context) error {
+		calls.Add(1)
+		return errors.New("permanent")
+	})
+	backoff := func(int) time.Duration { return time.Second }
+	if _, err := r.Cron.AddJob("@every 1h", job, "failing", better_cron.WithRetry(2, backoff)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	r.Advance(time.Hour + time.Minute)
+	r.ExpectRuns(t, "failing", 1, epoch, epoch.Add(2*time.Hour))
+	r.ExpectStatus(t, "failing", better_cron.StatusFailed)
+	if n := calls.Load(); n != 2 {
+		t.Errorf("job called %d times, want 2", n)
+	}
+}
+
+func TestPipelineCalendarSkip(t *testing.T) {
+	skips := &skipRecorder{}
+	r := bcrontest.New(t, epoch, better_cron.WithEventSink(better_cron.EventSinkFunc(skips.handle)))
+	defer r.Close()
+
+	// Friday 2026-01-02 is a holiday
+	cal := better_cron.NewHolidayCalendar(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	if _, err := r.Cron.AddJob("0 0 9 * * *", better_cron.ErrorFuncJob(func(context.Context) error { return nil }), "daily",
+		better_cron.WithCalendar(cal)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	r.Advance(3 * 24 * time.Hour)
+	r.ExpectRuns(t, "daily", 2, epoch, epoch.Add(3*24*time.Hour))
+	r.ExpectRuns(t, "daily", 0, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 2, 23, 59, 0, 0, time.UTC))
+	if n := skips.count(); n != 1 {
+		t.Errorf("%d fires skipped, want 1", n)
+	}
+}
+
+func TestPipelineCalendarDefer(t *testing.T) {
+	r := bcrontest.New(t, epoch)
+	defer r.Close()
+
+	// Monday 2026-01-05 is a holiday, so the weekly fire moves to Tuesday
+	monday := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	cal := better_cron.NewHolidayCalendar(monday)
+	if _, err := r.Cron.AddJob("0 0 9 * * 1", better_cron.ErrorFuncJob(func(context.Context) error { return nil }), "weekly",
+		better_cron.WithCalendar(cal), better_cron.WithHolidayPolicy(better_cron.HolidayDefer)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	r.AdvanceTo(monday.Add(25 * time.Hour))
+	runs := r.RunsOf("weekly")
+	if len(runs) != 1 {
+		t.Fatalf("%d runs recorded, want 1", len(runs))
+	}
+	if want := monday.AddDate(0, 0, 1); !runs[0].Start.Equal(want) {
+		t.Errorf("deferred run started at %v, want %v", runs[0].Start, want)
+	}
+}
+
+func TestPipelineCalendarDeferToScheduledFire(t *testing.T) {
+	skips := &skipRecorder{}
+	r := bcrontest.New(t, epoch, better_cron.WithEventSink(better_cron.EventSinkFunc(skips.handle)))
+	defer r.Close()
+
+	// A daily job fires on the next business day anyway, so the holiday
+	// fire is dropped instead of running twice then
+	holiday := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	cal := better_cron.NewHolidayCalendar(holiday)
+	if _, err := r.Cron.AddJob("0 0 9 * * *", better_cron.ErrorFuncJob(func(context.Context) error { return nil }), "daily",
+		better_cron.WithCalendar(cal), better_cron.WithHolidayPolicy(better_cron.HolidayDefer)); err != nil {
+		t.Fatal(err)
+	}
+	r.Start()
+
+	r.AdvanceTo(holiday.Add(26 * time.Hour))
+	r.ExpectRuns(t, "daily", 1, holiday, holiday.Add(26*time.Hour))
+	if n := skips.count(); n != 1 {
+		t.Errorf("%d fires skipped, want 1", n)
+	}
+}
@@ -0,0 +1,112 @@
+package better_cron
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/robfig/cron/v3"
+)
+
+// WithMaxConcurrency limits the scheduler to n simultaneously executing
+// runs across all jobs. Fires that arrive while all workers are busy wait
+// in a queue of n fires by default; see WithPoolQueueSize.
+func WithMaxConcurrency(n int) Option {
+	return func(ec *EnhancedCron) {
+		ec.poolSize = n
+		if ec.poolQueue < 0 {
+			ec.poolQueue = n
+		}
+	}
+}
+
+// WithPoolQueueSize sets how many fires may wait for a free worker. With a
+// size of 0, fires arriving while the pool is saturated are skipped.
+func WithPoolQueueSize(size int) Option {
+	return func(ec *EnhancedCron) {
+		ec.poolQueue = size
+	}
+}
+
+// workerPool runs submitted tasks on a fixed number of goroutines
+type workerPool struct {
+	tasks   chan func()
+	size    int
+	busy    int32
+	metrics MetricsRecorder
+}
+
+func newWorkerPool(size, queueSize int, metrics MetricsRecorder) *workerPool {
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	return &workerPool{
+		tasks:   make(chan func(), queueSize),
+		size:    size,
+		metrics: metrics,
+	}
+}
+
+// start launches the workers. Once ctx is done they run what is left in
+// the queue, which the executor skips during shutdown, and exit.
+func (p *workerPool) start(ctx context.Context) {
+	for i := 0; i < p.size; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					p.drain()
+					return
+				case task := <-p.tasks:
+					p.record(atomic.AddInt32(&p.busy, 1))
+					task()
+					p.record(atomic.AddInt32(&p.busy, -1))
+				}
+			}
+		}()
+	}
+}
+
+// drain runs queued tasks until the queue is empty
+func (p *workerPool) drain() {
+	for {
+		select {
+		case task := <-p.tasks:
+			task()
+		default:
+			return
+		}
+	}
+}
+
+// trySubmit hands task to the pool without blocking and reports whether
+// it was accepted
+func (p *workerPool) trySubmit(task func()) bool {
+	select {
+	case p.tasks <- task:
+		p.record(atomic.LoadInt32(&p.busy))
+		return true
+	default:
+		return false
+	}
+}
+
+// record publishes the pool gauges
+func (p *workerPool) record(busy int32) {
+	p.metrics.Gauge("pool.busy", float64(busy), nil)
+	p.metrics.Gauge("pool.queued", float64(len(p.tasks)), nil)
+	p.metrics.Gauge("pool.utilization", float64(busy)/float64(p.size), nil)
+}
+
+// submit executes a fire that holds an overlap slot, on the worker pool if
+// one is configured. A fire rejected by a saturated pool is skipped.
+func (ec *EnhancedCron) submit(job cron.Job, entry *jobEntry, f firing) {
+	if ec.pool == nil {
+		ec.execute(job, entry, f)
+		return
+	}
+
+	if !ec.pool.trySubmit(func() { ec.execute(job, entry, f) }) {
+		ec.emitSkipped(entry, "worker pool saturated")
+		ec.releaseOverlap(job, entry)
+	}
+}
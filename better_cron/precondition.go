@@ -0,0 +1,41 @@
+package better_cron
+
+import (
+	"context"
+	"time"
+)
+
+// Precondition decides whether a run of a job may start. A non-nil error
+// skips the run, with the error as the reason.
+type Precondition func(ctx context.Context) error
+
+// WithPrecondition checks check just before each run, after the scheduler's
+// own admission checks, e.g. to hold an export back until the upstream
+// table has refreshed. A failed check skips the run and emits an
+// EventJobSkipped with the error as the reason; the skipped run does not
+// count toward WithMaxRuns. Checks of a job are evaluated in order and
+// share the run's timeout.
+func WithPrecondition(check Precondition) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.preconditions = append(cfg.preconditions, check)
+	}
+}
+
+// checkPreconditions evaluates the job's preconditions, reporting false if
+// one failed and the run must be skipped
+func (ec *EnhancedCron) checkPreconditions(entry *jobEntry, timeout time.Duration) bool {
+	if len(entry.cfg.preconditions) == 0 {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ec.shutdownContext(), timeout)
+	defer cancel()
+	for _, check := range entry.cfg.preconditions {
+		if err := check(ctx); err != nil {
+			ec.logger.Info("skipping job %s: precondition failed: %v", entry.name, err)
+			ec.emitSkipped(entry, "precondition failed: "+err.Error())
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package better_cron
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxPreviewScan bounds how many fires PreviewRuns inspects, so a job whose
+// windows never open cannot loop forever
+const maxPreviewScan = 100000
+
+// PreviewRuns returns the next n times the job will run. Fires that the
+// job's time windows or holiday calendar would skip are left out.
+func (ec *EnhancedCron) PreviewRuns(name string, n int) ([]time.Time, error) {
+	ec.mu.RLock()
+	entry, ok := ec.jobs[name]
+	ec.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("job %q not found", name)
+	}
+
+	var runs []time.Time
+	t := ec.clock.Now().Round(0)
+	for i := 0; len(runs) < n && i < maxPreviewScan; i++ {
+		if t = entry.next(t); t.IsZero() {
+			break
+		}
+		if entry.skipReason(t) == "" {
+			runs = append(runs, t)
+		}
+	}
+	return runs, nil
+}
+
+// skipReason reports why a fire at t would be skipped by the job's time
+// windows or holiday calendar, or "" if it would run
+func (entry *jobEntry) skipReason(t time.Time) string {
+	if ok, reason := entry.checkWindows(t); !ok {
+		return reason
+	}
+	if cal := entry.cfg.calendar; cal != nil && cal.IsHoliday(t.In(entry.location)) {
+		return "holiday"
+	}
+	return ""
+}
+
+// PreviewSpec returns the next n fire times of a spec accepted by AddJob,
+// without registering anything
+func PreviewSpec(spec string, n int) ([]time.Time, error) {
+	schedule, err := parseSchedule(spec, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []time.Time
+	t := time.Now().Round(0)
+	for len(runs) < n {
+		if t = schedule.Next(t); t.IsZero() {
+			break
+		}
+		runs = append(runs, t)
+	}
+	return runs, nil
+}
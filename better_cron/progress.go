@@ -0,0 +1,90 @@
+package better_cron
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Progress is what a run last reported about how far it has got
+type Progress struct {
+	// Percent is the share of the work done, from 0 to 100
+	Percent float64
+	// Step describes the work under way, e.g. "uploading part 3/4"
+	Step    string
+	Updated time.Time
+}
+
+// Reporter lets a run report its progress. Each report is visible through
+// GetActiveJobs, GetJobStatus and the admin API, and is emitted as an
+// EventJobProgress.
+type Reporter interface {
+	Report(percent float64, step string)
+}
+
+// ReporterFromContext returns the progress reporter of the current run.
+// Outside a run it returns a reporter that discards everything.
+func ReporterFromContext(ctx context.Context) Reporter {
+	if reporter, ok := ctx.Value(reporterKey).(Reporter); ok {
+		return reporter
+	}
+	return nopReporter{}
+}
+
+type nopReporter struct{}
+
+func (nopReporter) Report(percent float64, step string) {}
+
+// runStatus holds the latest copy of a run's metadata. Only the run writes
+// its JobMetadata, and it publishes a copy after each change, so status
+// calls never read the metadata while it is being written.
+type runStatus struct {
+	current atomic.Pointer[JobMetadata]
+}
+
+// publish stores a copy of metadata as the run's current status
+func (s *runStatus) publish(metadata *JobMetadata) {
+	snapshot := *metadata
+	s.current.Store(&snapshot)
+}
+
+// load returns the last published metadata, which must not be modified
+func (s *runStatus) load() *JobMetadata {
+	return s.current.Load()
+}
+
+// progressReporter records the progress of one run
+type progressReporter struct {
+	ec     *EnhancedCron
+	entry  *jobEntry
+	status *runStatus
+	last   atomic.Pointer[Progress]
+}
+
+// Report stores the progress and emits it; it is called from the run
+func (r *progressReporter) Report(percent float64, step string) {
+	progress := &Progress{Percent: min(max(percent, 0), 100), Step: step, Updated: r.ec.clock.Now()}
+	r.last.Store(progress)
+
+	snapshot := *r.status.load()
+	snapshot.Progress = *progress
+	r.ec.emit(EventJobProgress, r.entry, &snapshot)
+}
+
+// load returns the last reported progress, or the zero Progress
+func (r *progressReporter) load() Progress {
+	if r == nil {
+		return Progress{}
+	}
+	if progress := r.last.Load(); progress != nil {
+		return *progress
+	}
+	return Progress{}
+}
+
+// snapshot copies the run's metadata with its latest progress
+func (job activeJob) snapshot() *JobMetadata {
+	metadata := *job.status.load()
+	metadata.Progress = job.progress.load()
+	return &metadata
+}
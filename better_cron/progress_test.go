@@ -0,0 +1,59 @@
+package better_cron_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cron_test/better_cron"
+)
+
+// Run with -race: status calls must only read published copies of a run's
+// metadata while the run keeps writing it
+func TestActiveJobsWhileRetrying(t *testing.T) {
+	ec := better_cron.NewEnhancedCron()
+	defer ec.Shutdown(context.Background(), better_cron.ShutdownAbort)
+
+	var calls atomic.Int32
+	done := make(chan struct{})
+	job := better_cron.ErrorFuncJob(func(ctx context.Context) error {
+		better_cron.ReporterFromContext(ctx).Report(float64(calls.Load()), "working")
+		if calls.Add(1) < 20 {
+			return errors.New("again")
+		}
+		close(done)
+		return nil
+	})
+	backoff := func(int) time.Duration { return time.Millisecond }
+	if _, err := ec.AddJob("@yearly", job, "retrying", better_cron.WithRetry(20, backoff)); err != nil {
+		t.Fatal(err)
+	}
+	ec.Start()
+	if err := ec.TriggerJob("retrying"); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := 0
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case <-done:
+			if seen < 2 {
+				t.Errorf("highest attempt seen while running is %d, want later attempts", seen)
+			}
+			return
+		case <-timeout:
+			t.Fatal("job did not finish")
+		default:
+		}
+		for _, m := range ec.GetActiveJobs() {
+			if m.Name == "retrying" && m.Status != better_cron.StatusRunning {
+				t.Errorf("active run has status %s", m.Status)
+			}
+			seen = max(seen, m.Attempt)
+		}
+		ec.GetJobStatus("retrying")
+	}
+}
@@ -0,0 +1,164 @@
+package better_cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// quartzSchedule extends a cron spec schedule with Quartz-style day
+// descriptors that cron cannot express:
+//
+//	day-of-month  L     last day of the month
+//	              L-3   third to last day of the month
+//	              15W   weekday nearest the 15th, within the month
+//	              LW    last weekday of the month
+//	day-of-week   5L    last Friday of the month (FRIL also works)
+//	              2#3   third Tuesday of the month (TUE#3 also works)
+type quartzSchedule struct {
+	base  *cron.SpecSchedule
+	match func(day time.Time) bool
+}
+
+// maxQuartzDays bounds the search for a matching day
+const maxQuartzDays = 5 * 366
+
+// hasQuartzDays reports whether a six-field spec uses L, W or # in its
+// day fields
+func hasQuartzDays(fields []string) bool {
+	if len(fields) != len(specFields) {
+		return false
+	}
+	dom, dow := strings.ToUpper(fields[3]), strings.ToUpper(fields[5])
+	return strings.ContainsAny(dom, "LW") || strings.ContainsAny(dow, "L#")
+}
+
+// parseQuartz parses a six-field spec with Quartz day descriptors. The other
+// day field must be * or ?.
+func parseQuartz(prefix string, fields []string) (*quartzSchedule, error) {
+	spec := strings.Join(fields, " ")
+	dom, dow := strings.ToUpper(fields[3]), strings.ToUpper(fields[5])
+	domSpecial := strings.ContainsAny(dom, "LW")
+
+	var match func(time.Time) bool
+	var err error
+	if domSpecial {
+		if !isWildcard(dow) {
+			return nil, fmt.Errorf("invalid spec %q: day-of-month %s requires day-of-week to be * or ?", spec, fields[3])
+		}
+		match, err = parseQuartzDayOfMonth(dom)
+	} else {
+		if !isWildcard(dom) {
+			return nil, fmt.Errorf("invalid spec %q: day-of-week %s requires day-of-month to be * or ?", spec, fields[5])
+		}
+		match, err = parseQuartzDayOfWeek(dow)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec %q: %v", spec, err)
+	}
+
+	base := append(append([]string(nil), fields[:3]...), "*", fields[4], "*")
+	schedule, err := specParser.Parse(prefix + strings.Join(base, " "))
+	if err != nil {
+		return nil, err
+	}
+	return &quartzSchedule{base: schedule.(*cron.SpecSchedule), match: match}, nil
+}
+
+// parseQuartzDayOfMonth parses L, L-n, nW and LW
+func parseQuartzDayOfMonth(field string) (func(time.Time) bool, error) {
+	switch {
+	case field == "L":
+		return func(d time.Time) bool { return d.Day() == lastDay(d) }, nil
+	case field == "LW":
+		return func(d time.Time) bool { return d.Day() == nearestWeekday(d, lastDay(d)) }, nil
+	case strings.HasPrefix(field, "L-"):
+		n, err := strconv.Atoi(field[2:])
+		if err != nil || n < 1 || n > 30 {
+			return nil, fmt.Errorf("day-of-month: invalid offset in %q", field)
+		}
+		return func(d time.Time) bool { return d.Day() == lastDay(d)-n }, nil
+	case strings.HasSuffix(field, "W"):
+		n, err := strconv.Atoi(field[:len(field)-1])
+		if err != nil || n < 1 || n > 31 {
+			return nil, fmt.Errorf("day-of-month: invalid day in %q", field)
+		}
+		return func(d time.Time) bool { return n <= lastDay(d) && d.Day() == nearestWeekday(d, n) }, nil
+	}
+	return nil, fmt.Errorf("day-of-month: unsupported descriptor %q", field)
+}
+
+// parseQuartzDayOfWeek parses dL and d#n
+func parseQuartzDayOfWeek(field string) (func(time.Time) bool, error) {
+	weekday := func(s string) (time.Weekday, error) {
+		if v, ok := specFields[5].names[strings.ToLower(s)]; ok {
+			return time.Weekday(v), nil
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 || v > 6 {
+			return 0, fmt.Errorf("day-of-week: invalid day %q", s)
+		}
+		return time.Weekday(v), nil
+	}
+
+	if day, nth, ok := strings.Cut(field, "#"); ok {
+		wd, err := weekday(day)
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(nth)
+		if err != nil || n < 1 || n > 5 {
+			return nil, fmt.Errorf("day-of-week: occurrence in %q must be 1-5", field)
+		}
+		return func(d time.Time) bool { return d.Weekday() == wd && (d.Day()-1)/7+1 == n }, nil
+	}
+	if strings.HasSuffix(field, "L") {
+		wd, err := weekday(field[:len(field)-1])
+		if err != nil {
+			return nil, err
+		}
+		return func(d time.Time) bool { return d.Weekday() == wd && d.Day()+7 > lastDay(d) }, nil
+	}
+	return nil, fmt.Errorf("day-of-week: unsupported descriptor %q", field)
+}
+
+// lastDay returns the number of days in d's month
+func lastDay(d time.Time) int {
+	return time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, d.Location()).Day()
+}
+
+// nearestWeekday returns the weekday closest to day n of d's month without
+// leaving the month
+func nearestWeekday(d time.Time, n int) int {
+	switch time.Date(d.Year(), d.Month(), n, 0, 0, 0, 0, d.Location()).Weekday() {
+	case time.Saturday:
+		if n == 1 {
+			return n + 2
+		}
+		return n - 1
+	case time.Sunday:
+		if n == lastDay(d) {
+			return n - 2
+		}
+		return n + 1
+	}
+	return n
+}
+
+// Next returns the first time after t that the base schedule fires on a
+// matching day
+func (s *quartzSchedule) Next(t time.Time) time.Time {
+	for i := 0; i < maxQuartzDays; i++ {
+		next := s.base.Next(t)
+		if next.IsZero() || s.match(next) {
+			return next
+		}
+		// Skip the rest of the day
+		y, m, d := next.Date()
+		t = time.Date(y, m, d+1, 0, 0, 0, 0, next.Location()).Add(-time.Nanosecond)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,60 @@
+package better_cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuartzScheduleNext(t *testing.T) {
+	from := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		want []time.Time
+	}{
+		{"0 0 12 L * ?", []time.Time{at(3, 31, 12), at(4, 30, 12), at(5, 31, 12)}},
+		{"0 0 12 L-1 * ?", []time.Time{at(3, 30, 12), at(4, 29, 12), at(5, 30, 12)}},
+		// The 15th of March is a Sunday
+		{"0 0 9 15W * ?", []time.Time{at(3, 16, 9), at(4, 15, 9), at(5, 15, 9)}},
+		// The 31st of May is a Sunday
+		{"0 0 9 LW 3-5 ?", []time.Time{at(3, 31, 9), at(4, 30, 9), at(5, 29, 9)}},
+		{"0 0 9 ? * 2#3", []time.Time{at(3, 17, 9), at(4, 21, 9), at(5, 19, 9)}},
+		{"0 0 9 ? * TUE#3", []time.Time{at(3, 17, 9), at(4, 21, 9), at(5, 19, 9)}},
+		{"0 0 18 ? * 5L", []time.Time{at(3, 27, 18), at(4, 24, 18), at(5, 29, 18)}},
+		{"0 0 18 ? * FRIL", []time.Time{at(3, 27, 18), at(4, 24, 18), at(5, 29, 18)}},
+	}
+	for _, tt := range tests {
+		schedule, err := parseSchedule(tt.spec, nil)
+		if err != nil {
+			t.Fatalf("parseSchedule(%q): %v", tt.spec, err)
+		}
+		if _, ok := schedule.(*quartzSchedule); !ok {
+			t.Fatalf("%q parsed as %T, want a Quartz schedule", tt.spec, schedule)
+		}
+		next := from
+		for i, want := range tt.want {
+			if next = schedule.Next(next); !next.Equal(want) {
+				t.Errorf("%q fire %d = %v, want %v", tt.spec, i+1, next, want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseQuartzErrors(t *testing.T) {
+	for _, spec := range []string{
+		"0 0 12 L * 1",
+		"0 0 12 1 * 5L",
+		"0 0 9 ? * 8#1",
+		"0 0 9 ? * 2#6",
+		"0 0 9 32W * ?",
+		"0 0 9 L-40 * ?",
+	} {
+		if _, err := parseSchedule(spec, nil); err == nil {
+			t.Errorf("parseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
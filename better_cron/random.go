@@ -0,0 +1,53 @@
+package better_cron
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// randomSchedule fires a random delay within [min, max] after each cycle
+type randomSchedule struct {
+	min, max time.Duration
+}
+
+// isRandomSpec reports whether spec uses the @randomly descriptor
+func isRandomSpec(spec string) bool {
+	return strings.HasPrefix(spec, "@randomly")
+}
+
+// parseRandomSpec parses "@randomly 5m-15m"
+func parseRandomSpec(spec string) (*randomSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 2 || fields[0] != "@randomly" {
+		return nil, fmt.Errorf("invalid schedule %q: expected @randomly <min>-<max>", spec)
+	}
+	lo, hi, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid schedule %q: expected a range such as 5m-15m", spec)
+	}
+
+	var s randomSchedule
+	var err error
+	if s.min, err = time.ParseDuration(lo); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+	}
+	if s.max, err = time.ParseDuration(hi); err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+	}
+	if s.min <= 0 || s.max < s.min {
+		return nil, fmt.Errorf("invalid schedule %q: range must be positive and not reversed", spec)
+	}
+	return &s, nil
+}
+
+// Next returns a random time between min and max after t
+func (s *randomSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.min + time.Duration(rand.Int63n(int64(s.max-s.min)+1)))
+}
+
+// String formats the schedule as a spec accepted by AddJob
+func (s *randomSchedule) String() string {
+	return fmt.Sprintf("@randomly %s-%s", s.min, s.max)
+}
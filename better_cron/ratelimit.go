@@ -0,0 +1,112 @@
+package better_cron
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits how many runs may start across all jobs to r per
+// second, allowing bursts of up to burst starts. Runs over the budget wait
+// for a token rather than being dropped. Fires skipped by a job's own
+// checks, such as its breaker or preconditions, take no token.
+func WithRateLimit(r float64, burst int) Option {
+	return func(ec *EnhancedCron) {
+		ec.limiter = newTokenBucket(r, burst)
+	}
+}
+
+// tokenBucket is a token bucket rate limiter shared by all jobs
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(r float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   r,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before
+// the token becomes valid
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.last.IsZero() {
+		b.last = now
+	}
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// refund gives back a token whose run did not start
+func (b *tokenBucket) refund() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens++; b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// wait blocks until a token is available or ctx is done, refunding the
+// token if ctx is done first
+func (b *tokenBucket) wait(ctx context.Context, clock Clock) error {
+	delay := b.reserve(clock.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := clock.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		b.refund()
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
+
+// waitForRateLimit holds a run until the shared rate limiter admits it and
+// reports false if the scheduler shut down while waiting. The held result
+// is set if the run took a token, which refundRateLimit gives back if the
+// run does not start after all.
+func (ec *EnhancedCron) waitForRateLimit(entry *jobEntry) (held, ok bool) {
+	if ec.limiter == nil {
+		return false, true
+	}
+
+	if err := ec.limiter.wait(ec.stoppingContext(), ec.clock); err != nil {
+		ec.emitSkipped(entry, "scheduler shutting down")
+		return false, false
+	}
+	return true, true
+}
+
+// refundRateLimit gives back the token of a run that did not start
+func (ec *EnhancedCron) refundRateLimit(held bool) {
+	if held {
+		ec.limiter.refund()
+	}
+}
@@ -0,0 +1,34 @@
+package better_cron
+
+import "errors"
+
+// ErrReadOnly is returned by operations rejected in read-only mode
+var ErrReadOnly = errors.New("scheduler is read-only")
+
+// WithReadOnly runs the scheduler as an observer, such as a dashboard
+// replica sharing the store of the scheduler that does the work. Jobs are
+// still registered so their schedules advance and can be listed, but
+// nothing fires, nothing is written to the store or audit log, operations
+// on runs and stored state return ErrReadOnly and the admin API rejects
+// every mutating request.
+func WithReadOnly() Option {
+	return func(ec *EnhancedCron) {
+		ec.readOnly = true
+	}
+}
+
+// WithReadOnlyAdmin rejects every mutating request to the admin API while
+// the scheduler itself keeps working, for giving broad read access safely
+func WithReadOnlyAdmin() AdminOption {
+	return func(cfg *adminConfig) {
+		cfg.readOnly = true
+	}
+}
+
+// writable returns ErrReadOnly in read-only mode
+func (ec *EnhancedCron) writable() error {
+	if ec.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
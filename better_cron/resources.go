@@ -0,0 +1,120 @@
+package better_cron
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+// resourceSampleInterval is how often the goroutine count is sampled
+// while a tracked run executes
+const resourceSampleInterval = 100 * time.Millisecond
+
+// ResourceUsage is the approximate cost of a run. The Go runtime only
+// measures the whole process, so runs that overlap are charged for each
+// other's work; CPU time of a Command's subprocess is exact and included.
+type ResourceUsage struct {
+	CPUTime        time.Duration
+	AllocBytes     uint64
+	PeakGoroutines int
+}
+
+// WithResourceTracking records the ResourceUsage of every run in its
+// JobMetadata and history
+func WithResourceTracking() Option {
+	return func(ec *EnhancedCron) {
+		ec.trackResources = true
+	}
+}
+
+// runtime metrics read around each tracked run
+const (
+	metricCPU        = "/cpu/classes/user:cpu-seconds"
+	metricGCCPU      = "/cpu/classes/gc/total:cpu-seconds"
+	metricAllocs     = "/gc/heap/allocs:bytes"
+	metricGoroutines = "/sched/goroutines:goroutines"
+)
+
+// usageTracker measures one run between start and stop
+type usageTracker struct {
+	start       []metrics.Sample
+	peak        int
+	done        chan struct{}
+	samplerDone chan struct{}
+}
+
+func readUsage() []metrics.Sample {
+	samples := []metrics.Sample{{Name: metricCPU}, {Name: metricGCCPU}, {Name: metricAllocs}, {Name: metricGoroutines}}
+	metrics.Read(samples)
+	return samples
+}
+
+// startUsage snapshots the runtime and samples the goroutine count in the
+// background until stop is called
+func startUsage() *usageTracker {
+	t := &usageTracker{start: readUsage(), done: make(chan struct{}), samplerDone: make(chan struct{})}
+	t.peak = sampleInt(t.start[3])
+	go t.sample()
+	return t
+}
+
+func (t *usageTracker) sample() {
+	defer close(t.samplerDone)
+	ticker := time.NewTicker(resourceSampleInterval)
+	defer ticker.Stop()
+
+	samples := []metrics.Sample{{Name: metricGoroutines}}
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			metrics.Read(samples)
+			t.peak = max(t.peak, sampleInt(samples[0]))
+		}
+	}
+}
+
+// stop ends the measurement and returns the usage since startUsage
+func (t *usageTracker) stop() ResourceUsage {
+	close(t.done)
+	<-t.samplerDone
+
+	end := readUsage()
+	cpu := sampleFloat(end[0]) - sampleFloat(t.start[0]) + sampleFloat(end[1]) - sampleFloat(t.start[1])
+	return ResourceUsage{
+		CPUTime:        time.Duration(cpu * float64(time.Second)),
+		AllocBytes:     sampleUint(end[2]) - sampleUint(t.start[2]),
+		PeakGoroutines: max(t.peak, sampleInt(end[3])),
+	}
+}
+
+// finishUsage stores the usage of a finished run, adding the CPU time of
+// a Command's subprocess
+func finishUsage(tracker *usageTracker, metadata *JobMetadata) {
+	if tracker == nil {
+		return
+	}
+	usage := tracker.stop()
+	if result, ok := metadata.Result.(CommandResult); ok {
+		usage.CPUTime += result.CPUTime
+	}
+	metadata.Resources = usage
+}
+
+func sampleFloat(s metrics.Sample) float64 {
+	if s.Value.Kind() != metrics.KindFloat64 {
+		return 0
+	}
+	return s.Value.Float64()
+}
+
+func sampleUint(s metrics.Sample) uint64 {
+	if s.Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return s.Value.Uint64()
+}
+
+func sampleInt(s metrics.Sample) int {
+	return int(sampleUint(s))
+}
@@ -0,0 +1,130 @@
+package better_cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// handoffEnv names the environment variable that carries the handoff file
+// from a restarting process to its replacement
+const handoffEnv = "BCRON_HANDOFF"
+
+// handoffState is what a restarting scheduler passes to its replacement
+type handoffState struct {
+	Jobs map[string]handoffJob `json:"jobs"`
+	// Definitions holds the jobs loaded from each job file
+	Definitions map[string][]JobDefinition `json:"definitions,omitempty"`
+	// Interrupted lists jobs whose runs were still going when the drain
+	// deadline passed; each is run once more after the restart
+	Interrupted []string  `json:"interrupted,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// handoffJob records where a job's schedule stood
+type handoffJob struct {
+	Prev time.Time `json:"prev"`
+	Next time.Time `json:"next"`
+}
+
+// handoff drains the scheduler and writes its state to a temporary file,
+// returning the file's path
+func (ec *EnhancedCron) handoff(ctx context.Context) (string, error) {
+	// Runs still going when the drain deadline passes stay in activeJobs
+	// and are handed over as interrupted
+	if err := ec.Shutdown(ctx, ShutdownDrain); err != nil {
+		ec.logger.Error("restart: drain incomplete, interrupted runs will resume: %v", err)
+	}
+
+	state := handoffState{
+		Jobs:        make(map[string]handoffJob),
+		Definitions: make(map[string][]JobDefinition),
+		Time:        ec.clock.Now(),
+	}
+	for _, job := range ec.ListJobs() {
+		state.Jobs[job.Name] = handoffJob{Prev: job.Prev, Next: job.Next}
+	}
+	ec.activeJobs.Range(func(key, value interface{}) bool {
+		state.Interrupted = append(state.Interrupted, value.(activeJob).metadata.Name)
+		return true
+	})
+
+	ec.configMu.Lock()
+	for source, defs := range ec.configDefs {
+		for _, def := range defs {
+			state.Definitions[source] = append(state.Definitions[source], def)
+		}
+	}
+	ec.configMu.Unlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "bcron-handoff-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// Resume picks up the state handed over by GracefulRestart. Call it after
+// registering code-defined jobs and calling Start. Jobs loaded from files
+// are restored, a fire that fell due during the restart runs once, and runs
+// that were cut off run again. Fires that already happened are not
+// repeated, since the new schedule only looks forward. Resume reports
+// false when the process was not started by a restart.
+func (ec *EnhancedCron) Resume() (bool, error) {
+	path := os.Getenv(handoffEnv)
+	if path == "" {
+		return false, nil
+	}
+	os.Unsetenv(handoffEnv)
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	var state handoffState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return false, fmt.Errorf("parse handoff %s: %w", path, err)
+	}
+
+	for source, defs := range state.Definitions {
+		if err := ec.reconcileDefinitions(source, defs); err != nil {
+			return true, fmt.Errorf("restore jobs from %s: %w", source, err)
+		}
+	}
+
+	now := ec.clock.Now()
+	resume := make(map[string]string)
+	for name, job := range state.Jobs {
+		if !job.Next.IsZero() && !job.Next.After(now) {
+			resume[name] = "missed during restart"
+		}
+	}
+	for _, name := range state.Interrupted {
+		resume[name] = "interrupted by restart"
+	}
+
+	for name, reason := range resume {
+		ec.mu.RLock()
+		entry, ok := ec.jobs[name]
+		ec.mu.RUnlock()
+		if !ok {
+			ec.logger.Error("restart: job %s is no longer registered", name)
+			continue
+		}
+		ec.logger.Info("restart: running job %s, %s", name, reason)
+		go ec.trigger(entry.job, entry, TriggerRestart)
+	}
+	return true, nil
+}
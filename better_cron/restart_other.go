@@ -0,0 +1,19 @@
+//go:build !unix
+
+package better_cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GracefulRestart is not supported where exec is unavailable
+func (ec *EnhancedCron) GracefulRestart(ctx context.Context) error {
+	return fmt.Errorf("graceful restart is not supported on this platform")
+}
+
+// RestartOnSignal is a no-op where SIGUSR2 and exec are unavailable
+func (ec *EnhancedCron) RestartOnSignal(drain time.Duration) (stop func()) {
+	return func() {}
+}
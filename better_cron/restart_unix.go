@@ -0,0 +1,65 @@
+//go:build unix
+
+package better_cron
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// GracefulRestart drains the scheduler until ctx expires, hands its state to
+// a fresh copy of the running binary and replaces the process with it via
+// exec. The new process should call Resume after Start. GracefulRestart only
+// returns if the handoff or exec fails, and the scheduler is shut down by
+// then either way.
+func (ec *EnhancedCron) GracefulRestart(ctx context.Context) error {
+	if err := ec.writable(); err != nil {
+		return err
+	}
+	path, err := ec.handoff(ctx)
+	if err != nil {
+		return err
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	env := append(os.Environ(), handoffEnv+"="+path)
+	ec.logger.Info("restart: exec %s", binary)
+	err = syscall.Exec(binary, os.Args, env)
+	os.Remove(path)
+	return err
+}
+
+// RestartOnSignal performs a GracefulRestart, draining for at most drain,
+// whenever the process receives SIGUSR2
+func (ec *EnhancedCron) RestartOnSignal(drain time.Duration) (stop func()) {
+	done := make(chan struct{})
+	usr2 := make(chan os.Signal, 1)
+	signal.Notify(usr2, syscall.SIGUSR2)
+
+	go func() {
+		defer signal.Stop(usr2)
+		select {
+		case <-done:
+		case <-ec.stoppingContext().Done():
+		case <-usr2:
+			ctx, cancel := context.WithTimeout(context.Background(), drain)
+			defer cancel()
+			if err := ec.GracefulRestart(ctx); err != nil {
+				ec.logger.Error("restart failed: %v", err)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
@@ -0,0 +1,162 @@
+package better_cron
+
+import (
+	"context"
+	"math/rand"
+	"runtime/debug"
+	"runtime/pprof"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ContextJob is a cron.Job that can also receive the context of its run,
+// which carries cancellation and values such as the attempt number
+type ContextJob interface {
+	cron.Job
+	RunContext(ctx context.Context)
+}
+
+// ContextFuncJob is a wrapper that turns a func(context.Context) into a ContextJob
+type ContextFuncJob func(ctx context.Context)
+
+// Run calls f with a background context
+func (f ContextFuncJob) Run() { f(context.Background()) }
+
+// RunContext calls f(ctx)
+func (f ContextFuncJob) RunContext(ctx context.Context) { f(ctx) }
+
+// ErrorJob is a cron.Job that reports failure by returning an error, which
+// populates JobMetadata.Error and drives retries
+type ErrorJob interface {
+	cron.Job
+	RunE(ctx context.Context) error
+}
+
+// ErrorFuncJob is a wrapper that turns a func(context.Context) error into an
+// ErrorJob. A method value such as ErrorFuncJob(task.RunE) adapts any type
+// with a RunE(ctx) error method.
+type ErrorFuncJob func(ctx context.Context) error
+
+// Run calls f with a background context and discards the error
+func (f ErrorFuncJob) Run() { _ = f(context.Background()) }
+
+// RunE calls f(ctx)
+func (f ErrorFuncJob) RunE(ctx context.Context) error { return f(ctx) }
+
+// ResultJob is a cron.Job that produces a result payload, such as a row
+// count or file path, which is attached to the run's metadata and history
+type ResultJob interface {
+	cron.Job
+	RunResult(ctx context.Context) (interface{}, error)
+}
+
+// ResultFuncJob is a wrapper that turns a func(context.Context) (interface{}, error)
+// into a ResultJob
+type ResultFuncJob func(ctx context.Context) (interface{}, error)
+
+// Run calls f with a background context and discards the result
+func (f ResultFuncJob) Run() { _, _ = f(context.Background()) }
+
+// RunResult calls f(ctx)
+func (f ResultFuncJob) RunResult(ctx context.Context) (interface{}, error) { return f(ctx) }
+
+// BackoffPolicy returns how long to wait after the given failed attempt
+type BackoffPolicy func(attempt int) time.Duration
+
+// ExponentialBackoff doubles the delay after every attempt starting at base,
+// caps it at max and randomizes the upper half to avoid retry storms
+func ExponentialBackoff(base, max time.Duration) BackoffPolicy {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		half := delay / 2
+		if half <= 0 {
+			return delay
+		}
+		return half + time.Duration(rand.Int63n(int64(half)+1))
+	}
+}
+
+// WithRetry retries a failed run up to maxAttempts attempts in total,
+// waiting according to backoff between attempts
+func WithRetry(maxAttempts int, backoff BackoffPolicy) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.maxAttempts = maxAttempts
+		cfg.backoff = backoff
+	}
+}
+
+// runWithRetry runs job until it succeeds, runs out of attempts or ctx is
+// done, publishing the metadata to status as each attempt starts and fails
+func (ec *EnhancedCron) runWithRetry(ctx context.Context, job cron.Job, entry *jobEntry, metadata *JobMetadata, status *runStatus) error {
+	cfg := entry.cfg
+	maxAttempts := cfg.maxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		metadata.Attempt = attempt
+		status.publish(metadata)
+		attemptCtx := context.WithValue(ctx, attemptKey, attempt)
+		attemptCtx = context.WithValue(attemptCtx, loggerKey, newRunLogger(ec.runLoggerBase(entry), metadata))
+		var result interface{}
+		var err error
+		// Label the attempt so CPU and goroutine profiles attribute it to the job
+		pprof.Do(attemptCtx, pprof.Labels("job", metadata.Name, "run_id", metadata.RunID), func(ctx context.Context) {
+			result, err = runJob(ctx, job)
+		})
+		metadata.Result = result
+		ec.handlePanic(metadata, err)
+		if err == nil || attempt >= maxAttempts {
+			return err
+		}
+
+		var delay time.Duration
+		if cfg.backoff != nil {
+			delay = cfg.backoff(attempt)
+		}
+		metadata.Error = err
+		status.publish(metadata)
+		ec.emit(EventJobRetrying, entry, metadata)
+		ec.logger.Error("job %s attempt %d/%d failed: %v, retrying in %v", metadata.Name, attempt, maxAttempts, err, delay)
+
+		timer := ec.clock.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C():
+		}
+	}
+}
+
+// runJob executes a single attempt using the richest interface the job
+// implements and turns a panic into an error
+func runJob(ctx context.Context, job cron.Job) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	switch j := job.(type) {
+	case ResultJob:
+		return j.RunResult(ctx)
+	case interface{ RunE(context.Context) error }:
+		return nil, j.RunE(ctx)
+	case interface{ RunE() error }:
+		return nil, j.RunE()
+	case ContextJob:
+		j.RunContext(ctx)
+	default:
+		job.Run()
+	}
+	return nil, nil
+}
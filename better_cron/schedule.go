@@ -0,0 +1,72 @@
+package better_cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// parseSchedule parses any spec accepted by AddJob, evaluating it in loc
+// when one is given. A leading TZ= or CRON_TZ= field applies to every form.
+func parseSchedule(spec string, loc *time.Location) (cron.Schedule, error) {
+	prefix, body := splitTimezone(spec)
+	if isISOInterval(body) {
+		// The anchor carries its own offset, so no zone applies
+		return parseISOInterval(body)
+	}
+	if isRandomSpec(body) {
+		return parseRandomSpec(body)
+	}
+	if isSunSpec(body) {
+		return parseSunSpec(body)
+	}
+	if isNaturalSpec(body) {
+		translated, err := translateNatural(body)
+		if err != nil {
+			return nil, err
+		}
+		spec, body = prefix+translated, translated
+	}
+	if fields := strings.Fields(body); hasQuartzDays(fields) {
+		schedule, err := parseQuartz(prefix, fields)
+		if err != nil {
+			return nil, err
+		}
+		if loc != nil {
+			schedule.base.Location = loc
+		}
+		return schedule, nil
+	}
+
+	schedule, err := specParser.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := schedule.(*cron.SpecSchedule); ok && loc != nil {
+		s.Location = loc
+	}
+	return schedule, nil
+}
+
+// splitTimezone separates a TZ= or CRON_TZ= prefix, including its trailing
+// space, from the rest of the spec
+func splitTimezone(spec string) (prefix, body string) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		if i := strings.IndexByte(spec, ' '); i >= 0 {
+			return spec[:i+1], strings.TrimSpace(spec[i+1:])
+		}
+	}
+	return "", spec
+}
+
+// describeSchedule names a custom schedule for ListJobs, using its String
+// method when it has one
+func describeSchedule(schedule cron.Schedule) string {
+	if s, ok := schedule.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", schedule)
+}
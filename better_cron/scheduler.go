@@ -0,0 +1,256 @@
+package better_cron
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// scheduler is the run loop behind EnhancedCron. It follows robfig/cron's
+// loop, firing each due entry in its own goroutine, but reads time from a
+// Clock so it can be driven by a FakeClock. Like robfig, it reports start,
+// stop and entry changes through its logger.
+type scheduler struct {
+	clock  Clock
+	logger cron.Logger
+
+	mu      sync.Mutex
+	entries map[cron.EntryID]*cron.Entry
+	nextID  cron.EntryID
+	running bool
+	observe bool
+	stop    chan struct{}
+	wake    chan struct{}
+	// timer is the run loop's armed timer, stopped by poke
+	timer Timer
+
+	// jobs counts the fires of the current run cycle. Each Start begins a
+	// new one, so a Stop still waiting never races a later cycle's fires.
+	jobs *sync.WaitGroup
+}
+
+func newScheduler(clock Clock, logger cron.Logger) *scheduler {
+	return &scheduler{
+		clock:   clock,
+		logger:  logger,
+		entries: make(map[cron.EntryID]*cron.Entry),
+		wake:    make(chan struct{}, 1),
+		jobs:    &sync.WaitGroup{},
+	}
+}
+
+// Schedule adds a job and returns its entry ID
+func (s *scheduler) Schedule(schedule cron.Schedule, job cron.Job) cron.EntryID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	entry := &cron.Entry{ID: s.nextID, Schedule: schedule, Job: job}
+	if s.running {
+		entry.Next = schedule.Next(s.clock.Now())
+		s.logger.Info("schedule", "entry", entry.ID, "next", entry.Next)
+	} else {
+		s.logger.Info("schedule", "entry", entry.ID)
+	}
+	s.entries[entry.ID] = entry
+	s.poke()
+	return entry.ID
+}
+
+// Remove drops an entry; a fire already started is not affected
+func (s *scheduler) Remove(id cron.EntryID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[id]; ok {
+		s.logger.Info("removed", "entry", id)
+	}
+	delete(s.entries, id)
+	s.poke()
+}
+
+// Entry returns a snapshot of an entry, or the zero Entry if it is unknown
+func (s *scheduler) Entry(id cron.EntryID) cron.Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[id]; ok {
+		return *entry
+	}
+	return cron.Entry{}
+}
+
+// Start computes every entry's next fire and starts the run loop. The next
+// fires are known once Start returns. It reports false if already running.
+func (s *scheduler) Start() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return false
+	}
+
+	s.running = true
+	s.stop = make(chan struct{})
+	s.jobs = &sync.WaitGroup{}
+	s.logger.Info("start", "entries", len(s.entries))
+	now := s.clock.Now()
+	for _, entry := range s.entries {
+		entry.Next = entry.Schedule.Next(now)
+	}
+	go s.run(s.stop)
+	return true
+}
+
+// Run fires job once outside its schedule; Stop waits for it like any fire
+func (s *scheduler) Run(job cron.Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.running || s.observe {
+		return
+	}
+	jobs := s.jobs
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		job.Run()
+	}()
+}
+
+// Stop halts the run loop. The returned context is done once every fire
+// already started has returned.
+func (s *scheduler) Stop() context.Context {
+	s.mu.Lock()
+	if s.running {
+		s.running = false
+		close(s.stop)
+		s.logger.Info("stop")
+	}
+	jobs := s.jobs
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		jobs.Wait()
+		cancel()
+	}()
+	return ctx
+}
+
+// dueJob is a job that is told when its fire was due, which is later than
+// the fire starts when the loop runs late
+type dueJob interface {
+	runDue(due time.Time)
+}
+
+// poke wakes the run loop so it picks up a changed entry set; callers hold
+// mu. The armed timer is stopped right away, so no timer is pending for a
+// fire that is gone while the loop catches up.
+func (s *scheduler) poke() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *scheduler) run(stop chan struct{}) {
+	for {
+		var fire <-chan time.Time
+		timer := s.arm()
+		if timer != nil {
+			fire = timer.C()
+		}
+
+		select {
+		case <-fire:
+			s.fireDue()
+		case <-s.wake:
+		case <-stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// arm sets the timer for the soonest next fire, returning nil if none is due
+func (s *scheduler) arm() Timer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next time.Time
+	for _, entry := range s.entries {
+		if !entry.Next.IsZero() && (next.IsZero() || entry.Next.Before(next)) {
+			next = entry.Next
+		}
+	}
+	s.timer = nil
+	if !next.IsZero() {
+		s.timer = s.clock.NewTimer(next.Sub(s.clock.Now()))
+	}
+	return s.timer
+}
+
+// fireDue starts every entry whose fire time has passed and schedules its
+// next one. Fires missed while the loop was busy are not caught up.
+func (s *scheduler) fireDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A fire racing Stop must not start once Stop is waiting for the others
+	if !s.running {
+		return
+	}
+
+	now := s.clock.Now()
+	for _, entry := range s.entries {
+		if entry.Next.IsZero() || entry.Next.After(now) {
+			continue
+		}
+		// An observer advances the schedule without firing
+		if !s.observe {
+			job, due, jobs := entry.Job, entry.Next, s.jobs
+			jobs.Add(1)
+			go func() {
+				defer jobs.Done()
+				if j, ok := job.(dueJob); ok {
+					j.runDue(due)
+					return
+				}
+				job.Run()
+			}()
+		}
+		entry.Prev = entry.Next
+		entry.Next = entry.Schedule.Next(now)
+	}
+}
+
+// setPrev records when an entry last fired, as restored from a snapshot
+func (s *scheduler) setPrev(id cron.EntryID, prev time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[id]; ok && entry.Prev.Before(prev) {
+		entry.Prev = prev
+	}
+}
+
+// reschedule recomputes an entry's next fire, for schedules that depend on
+// job state, and returns it
+func (s *scheduler) reschedule(id cron.EntryID) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[id]
+	if !ok || !s.running {
+		return time.Time{}
+	}
+	entry.Next = entry.Schedule.Next(s.clock.Now())
+	s.poke()
+	return entry.Next
+}
@@ -0,0 +1,29 @@
+package better_cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestSchedulerRestartWhileStopping(t *testing.T) {
+	s := newScheduler(NewFakeClock(epoch), cron.DiscardLogger)
+	first, second := make(chan struct{}), make(chan struct{})
+	defer close(second)
+
+	s.Start()
+	s.Run(cron.FuncJob(func() { <-first }))
+	stopped := s.Stop()
+
+	// The next cycle's fire must not hold up the Stop of the one before
+	s.Start()
+	s.Run(cron.FuncJob(func() { <-second }))
+	close(first)
+	select {
+	case <-stopped.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop still waiting for a fire of the next run cycle")
+	}
+	s.Stop()
+}
@@ -0,0 +1,64 @@
+package better_cron
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ShutdownProgress is a snapshot of a shutdown that is waiting for jobs
+type ShutdownProgress struct {
+	Elapsed time.Duration
+	Timeout time.Duration
+	// Running holds the runs still executing, oldest first
+	Running []JobMetadata
+}
+
+// WithShutdownProgress reports shutdown progress every interval while
+// Shutdown waits for running jobs. Progress is always logged; fn, when not
+// nil, receives it as well.
+func WithShutdownProgress(interval time.Duration, fn func(ShutdownProgress)) Option {
+	return func(ec *EnhancedCron) {
+		ec.progressInterval = interval
+		ec.progressFunc = fn
+	}
+}
+
+// shutdownProgress captures the runs still executing
+func (ec *EnhancedCron) shutdownProgress(started, deadline time.Time) ShutdownProgress {
+	progress := ShutdownProgress{
+		Elapsed: time.Since(started),
+		Timeout: deadline.Sub(started),
+	}
+	ec.activeJobs.Range(func(key, value interface{}) bool {
+		progress.Running = append(progress.Running, *value.(activeJob).snapshot())
+		return true
+	})
+	sort.Slice(progress.Running, func(i, j int) bool {
+		return progress.Running[i].StartTime.Before(progress.Running[j].StartTime)
+	})
+	return progress
+}
+
+// reportShutdown logs progress and hands it to the progress callback
+func (ec *EnhancedCron) reportShutdown(progress ShutdownProgress) {
+	ec.logger.Info("shutdown: waiting for %d job(s) after %v of %v: %s",
+		len(progress.Running), progress.Elapsed.Round(100*time.Millisecond), progress.Timeout.Round(100*time.Millisecond), progress.describeRunning())
+	if ec.progressFunc != nil {
+		ec.progressFunc(progress)
+	}
+}
+
+// describeRunning lists the running jobs with how long each has been going
+func (p ShutdownProgress) describeRunning() string {
+	if len(p.Running) == 0 {
+		return "none"
+	}
+	now := time.Now()
+	parts := make([]string, len(p.Running))
+	for i, run := range p.Running {
+		parts[i] = fmt.Sprintf("%s (run %s, %v)", run.Name, run.RunID, now.Sub(run.StartTime).Round(100*time.Millisecond))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,111 @@
+package better_cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// WithSLA requires the job to complete successfully before every deadline
+// of the cron spec, e.g. WithSLA("0 0 6 * * *", time.Hour) for "done by
+// 06:00 daily". The spec uses the job's timezone. When a deadline passes
+// with no successful run since the previous one, an EventSLAMissed is
+// emitted, whether the job failed or never ran; if warning is positive, an
+// EventSLAAtRisk is emitted that long before the deadline.
+func WithSLA(deadline string, warning time.Duration) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.slaSpec = deadline
+		cfg.slaWarning = warning
+	}
+}
+
+// earlySchedule fires a fixed duration before each fire of a schedule
+type earlySchedule struct {
+	cron.Schedule
+	by time.Duration
+}
+
+// Next returns the first shifted fire after t
+func (s earlySchedule) Next(t time.Time) time.Time {
+	next := s.Schedule.Next(t.Add(s.by))
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(-s.by)
+}
+
+// parseSLA parses the job's deadline spec, if it has one
+func parseSLA(name string, cfg *jobConfig) (cron.Schedule, error) {
+	if cfg.slaSpec == "" {
+		return nil, nil
+	}
+	schedule, err := parseSchedule(cfg.slaSpec, cfg.location)
+	if err != nil {
+		return nil, fmt.Errorf("job %q sla deadline: %w", name, err)
+	}
+	return schedule, nil
+}
+
+// scheduleSLA registers the watchdog entries that check the job's deadlines;
+// callers hold ec.mu
+func (ec *EnhancedCron) scheduleSLA(entry *jobEntry, deadline cron.Schedule) {
+	if deadline == nil {
+		return
+	}
+
+	entry.mu.Lock()
+	if entry.slaPeriodStart.IsZero() {
+		entry.slaPeriodStart = ec.clock.Now()
+	}
+	entry.mu.Unlock()
+
+	entry.slaIDs = append(entry.slaIDs, ec.sched.Schedule(deadline, cron.FuncJob(func() {
+		ec.checkSLA(entry, true)
+	})))
+	if entry.cfg.slaWarning > 0 {
+		entry.slaIDs = append(entry.slaIDs, ec.sched.Schedule(earlySchedule{deadline, entry.cfg.slaWarning}, cron.FuncJob(func() {
+			ec.checkSLA(entry, false)
+		})))
+	}
+}
+
+// checkSLA emits an SLA event unless the job succeeded in the current
+// deadline period. At the deadline itself, the next period begins.
+func (ec *EnhancedCron) checkSLA(entry *jobEntry, atDeadline bool) {
+	now := ec.clock.Now()
+	entry.mu.Lock()
+	met := entry.lastSuccess.After(entry.slaPeriodStart)
+	since := entry.slaPeriodStart
+	if atDeadline {
+		entry.slaPeriodStart = now
+	}
+	entry.mu.Unlock()
+	if met {
+		return
+	}
+
+	eventType, reason := EventSLAAtRisk, fmt.Sprintf("no successful run since %s; deadline in %v", since.Format(time.RFC3339), entry.cfg.slaWarning)
+	if atDeadline {
+		eventType, reason = EventSLAMissed, fmt.Sprintf("no successful run between %s and %s", since.Format(time.RFC3339), now.Format(time.RFC3339))
+		ec.logger.Error("job %s missed its SLA: %s", entry.name, reason)
+	}
+	ec.dispatch(entry, JobEvent{
+		Type:     eventType,
+		Job:      entry.name,
+		Tags:     entry.cfg.tags,
+		Time:     now,
+		Metadata: JobMetadata{ID: entry.id, Name: entry.name, Status: StatusIdle},
+		Reason:   reason,
+	})
+}
+
+// recordSuccess notes the end time of a successful run for SLA checks
+func (ec *EnhancedCron) recordSuccess(entry *jobEntry, metadata *JobMetadata) {
+	if metadata.Status != StatusCompleted {
+		return
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	entry.lastSuccess = metadata.EndTime
+}
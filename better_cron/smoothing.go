@@ -0,0 +1,52 @@
+package better_cron
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// WithLoadSmoothing staggers jobs that fire at the same instant as another
+// job, delaying each scheduled fire by up to window so their load does not
+// spike at once. The delay is derived from the job name, so a job always
+// fires at the same offset. Jobs firing alone keep their exact times, and
+// manual, restart and other unscheduled fires are never delayed.
+func WithLoadSmoothing(window time.Duration) Option {
+	return func(ec *EnhancedCron) {
+		ec.smoothing = window
+	}
+}
+
+// WithoutLoadSmoothing keeps the job on its exact schedule under
+// WithLoadSmoothing. It still counts as co-scheduled for other jobs.
+func WithoutLoadSmoothing() JobOption {
+	return func(cfg *jobConfig) {
+		cfg.noSmoothing = true
+	}
+}
+
+// smoothingDelay returns how long to hold a fire back so it does not start
+// together with the other jobs scheduled at the same instant
+func (ec *EnhancedCron) smoothingDelay(entry *jobEntry, f firing) time.Duration {
+	if ec.smoothing <= 0 || entry.cfg.noSmoothing || f.trigger != TriggerSchedule {
+		return 0
+	}
+
+	instant := f.scheduled
+	before := instant.Add(-time.Nanosecond)
+	ec.mu.RLock()
+	shared := false
+	for _, other := range ec.jobs {
+		if other.jobState != entry.jobState && other.next(before).Equal(instant) {
+			shared = true
+			break
+		}
+	}
+	ec.mu.RUnlock()
+	if !shared {
+		return 0
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(entry.name))
+	return time.Duration(h.Sum64() % uint64(ec.smoothing))
+}
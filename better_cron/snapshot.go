@@ -0,0 +1,218 @@
+package better_cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// snapshotVersion is the format version of Snapshot's output
+const snapshotVersion = 1
+
+// schedulerSnapshot is the state Snapshot captures
+type schedulerSnapshot struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	// Definitions holds the jobs loaded from each job file
+	Definitions map[string][]JobDefinition `json:"definitions,omitempty"`
+	// Jobs holds the jobs added in code, in the form ExportJobs writes
+	Jobs        []JobDefinition        `json:"jobs,omitempty"`
+	State       map[string]snapshotJob `json:"state"`
+	DeadLetters []DeadLetter           `json:"dead_letters,omitempty"`
+}
+
+// snapshotJob is the runtime state of one job
+type snapshotJob struct {
+	Prev time.Time `json:"prev"`
+	Next time.Time `json:"next"`
+	// Runs counts the runs toward WithMaxRuns
+	Runs   int              `json:"runs,omitempty"`
+	Queued []snapshotFiring `json:"queued,omitempty"`
+	Paused bool             `json:"paused,omitempty"`
+}
+
+// snapshotFiring is a fire waiting in a job's overlap queue
+type snapshotFiring struct {
+	Scheduled time.Time `json:"scheduled"`
+	Trigger   string    `json:"trigger"`
+}
+
+// Snapshot captures the scheduler's state as JSON so an instance can be
+// rebuilt with Restore after losing its host: the job definitions, when
+// each job last and next fires, its run count and pause, the fires waiting in its
+// overlap queue and, with a store, the dead-lettered runs awaiting a
+// redrive. Jobs added in code are captured as ExportJobs writes them.
+func (ec *EnhancedCron) Snapshot() ([]byte, error) {
+	state := schedulerSnapshot{
+		Version:     snapshotVersion,
+		Time:        ec.clock.Now(),
+		Definitions: make(map[string][]JobDefinition),
+		State:       make(map[string]snapshotJob),
+	}
+
+	ec.configMu.Lock()
+	defined := make(map[string]bool)
+	for source, defs := range ec.configDefs {
+		for name, def := range defs {
+			state.Definitions[source] = append(state.Definitions[source], def)
+			defined[name] = true
+		}
+	}
+	ec.configMu.Unlock()
+
+	ec.mu.RLock()
+	for name, entry := range ec.jobs {
+		if !defined[name] {
+			if _, err := ValidateSpec(entry.spec); err == nil {
+				def, _ := entry.definition()
+				state.Jobs = append(state.Jobs, def)
+			}
+		}
+
+		scheduled := ec.sched.Entry(entry.id)
+		job := snapshotJob{Prev: scheduled.Prev, Next: scheduled.Next}
+		entry.mu.Lock()
+		job.Runs = entry.runs
+		job.Paused = entry.paused
+		for _, f := range entry.queue {
+			job.Queued = append(job.Queued, snapshotFiring{Scheduled: f.scheduled, Trigger: f.trigger})
+		}
+		entry.mu.Unlock()
+		state.State[name] = job
+	}
+	ec.mu.RUnlock()
+
+	if ec.store != nil {
+		letters, err := ec.ListDeadLetters("")
+		if err != nil {
+			return nil, fmt.Errorf("snapshot dead letters: %w", err)
+		}
+		state.DeadLetters = letters
+	}
+	return json.Marshal(state)
+}
+
+// Restore rebuilds the state captured by Snapshot. Call it after
+// registering code-defined jobs and calling Start. Jobs loaded from files
+// are restored, and jobs added in code that are not registered yet are
+// added from their definitions when a job func of their name is
+// registered. Run counts and last fire times carry over, a fire that fell
+// due since the snapshot runs once, queued fires run again and dead
+// letters are written back to the store.
+func (ec *EnhancedCron) Restore(data []byte) error {
+	if err := ec.writable(); err != nil {
+		return err
+	}
+	err := ec.restore(data)
+	ec.recordAction(AdminAction{Actor: ActorAPI, Action: "restore"}, err)
+	return err
+}
+
+func (ec *EnhancedCron) restore(data []byte) error {
+	var state schedulerSnapshot
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("parse snapshot: %w", err)
+	}
+	if state.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", state.Version)
+	}
+
+	for source, defs := range state.Definitions {
+		if err := ec.reconcileDefinitions(source, defs); err != nil {
+			return fmt.Errorf("restore jobs from %s: %w", source, err)
+		}
+	}
+	if err := ec.restoreCodeJobs(state.Jobs); err != nil {
+		return err
+	}
+
+	if len(state.DeadLetters) > 0 && ec.store == nil {
+		ec.logger.Error("restore: no store configured, dropping %d dead letters", len(state.DeadLetters))
+	}
+	for _, letter := range state.DeadLetters {
+		if ec.store == nil {
+			break
+		}
+		if err := ec.restoreDeadLetter(letter); err != nil {
+			return fmt.Errorf("restore dead letter %s: %w", letter.ID, err)
+		}
+	}
+
+	now := ec.clock.Now()
+	for name, job := range state.State {
+		ec.mu.RLock()
+		entry, ok := ec.jobs[name]
+		ec.mu.RUnlock()
+		if !ok {
+			ec.logger.Error("restore: job %s is no longer registered", name)
+			continue
+		}
+
+		ec.sched.setPrev(entry.id, job.Prev)
+		entry.mu.Lock()
+		if job.Runs > entry.runs {
+			entry.runs = job.Runs
+		}
+		entry.paused = job.Paused
+		entry.mu.Unlock()
+		if job.Paused {
+			continue
+		}
+
+		if !job.Next.IsZero() && !job.Next.After(now) {
+			ec.logger.Info("restore: running job %s, missed since the snapshot", name)
+			go ec.triggerAt(entry.job, entry, TriggerRestore, job.Next)
+		}
+		for _, queued := range job.Queued {
+			f := firing{scheduled: queued.Scheduled, trigger: TriggerRestore}
+			ec.recordTrigger(entry, f)
+			go ec.fire(entry.job, entry, f)
+		}
+	}
+	return nil
+}
+
+// restoreCodeJobs adds the snapshotted code jobs that are not registered
+func (ec *EnhancedCron) restoreCodeJobs(defs []JobDefinition) error {
+	var missing []JobDefinition
+	for _, def := range defs {
+		ec.mu.RLock()
+		_, exists := ec.jobs[def.qualifiedName()]
+		ec.mu.RUnlock()
+		if exists || (!def.EndAt.IsZero() && !ec.clock.Now().Before(def.EndAt)) {
+			continue
+		}
+
+		jobFuncs.RLock()
+		_, ok := jobFuncs.jobs[def.Func]
+		jobFuncs.RUnlock()
+		if !ok {
+			ec.logger.Error("restore: job %s has no registered job func, not restoring it", def.qualifiedName())
+			continue
+		}
+		missing = append(missing, def)
+	}
+
+	jobs, err := ec.buildDefinitions(missing)
+	if err != nil {
+		return err
+	}
+	for _, b := range jobs {
+		if _, err := ec.AddJob(b.def.Spec, b.job, b.def.qualifiedName(), append(b.opts, WithAuditActor("restore"))...); err != nil {
+			return fmt.Errorf("restore job %s: %w", b.def.qualifiedName(), err)
+		}
+	}
+	return nil
+}
+
+// restoreDeadLetter writes a dead letter back unless it is already stored
+func (ec *EnhancedCron) restoreDeadLetter(letter DeadLetter) error {
+	if _, ok, err := ec.loadDeadLetter(letter.ID); err != nil || ok {
+		return err
+	}
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+	return ec.store.Put(deadLetterPrefix+letter.ID, data)
+}
@@ -0,0 +1,128 @@
+package better_cron
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// StatsDEmitter pushes scheduler metrics to a StatsD or DogStatsD agent over
+// UDP. As an EventSink it reports job.duration (a timer in milliseconds)
+// and job.success or job.failure (counters) for every finished run, tagged
+// with the job name and the job's tags. It is also a MetricsRecorder, so
+// passing it to WithMetrics sends the queue and pool gauges too.
+type StatsDEmitter struct {
+	conn   net.Conn
+	prefix string
+	tags   []string
+	plain  bool
+	logger Logger
+}
+
+// StatsDOption represents configuration options for StatsDEmitter
+type StatsDOption func(*StatsDEmitter)
+
+// WithStatsDPrefix prepends prefix and a dot to every metric name
+func WithStatsDPrefix(prefix string) StatsDOption {
+	return func(e *StatsDEmitter) {
+		e.prefix = strings.TrimSuffix(prefix, ".") + "."
+	}
+}
+
+// WithStatsDTags adds constant tags, such as "env:prod", to every metric
+func WithStatsDTags(tags ...string) StatsDOption {
+	return func(e *StatsDEmitter) {
+		e.tags = append(e.tags, tags...)
+	}
+}
+
+// WithPlainStatsD drops tags for agents that only speak the original StatsD
+// protocol; by default tags are sent in the DogStatsD format
+func WithPlainStatsD() StatsDOption {
+	return func(e *StatsDEmitter) {
+		e.plain = true
+	}
+}
+
+// WithStatsDLogger sets a logger for send errors
+func WithStatsDLogger(logger Logger) StatsDOption {
+	return func(e *StatsDEmitter) {
+		e.logger = logger
+	}
+}
+
+// NewStatsDEmitter creates an emitter sending to the agent at addr, e.g.
+// "127.0.0.1:8125"
+func NewStatsDEmitter(addr string, opts ...StatsDOption) (*StatsDEmitter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial statsd %s: %w", addr, err)
+	}
+	e := &StatsDEmitter{conn: conn}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
+}
+
+// HandleEvent reports the duration and outcome of finished runs
+func (e *StatsDEmitter) HandleEvent(event JobEvent) {
+	var outcome string
+	switch event.Type {
+	case EventJobCompleted:
+		outcome = "job.success"
+	case EventJobFailed:
+		outcome = "job.failure"
+	default:
+		return
+	}
+
+	tags := append([]string{"job:" + event.Job}, event.Tags...)
+	m := event.Metadata
+	if !m.StartTime.IsZero() && !m.EndTime.IsZero() {
+		ms := float64(m.EndTime.Sub(m.StartTime).Microseconds()) / 1000
+		e.send("job.duration", ms, "ms", tags)
+	}
+	e.send(outcome, 1, "c", tags)
+}
+
+// Gauge sends a gauge, so the emitter can be used with WithMetrics
+func (e *StatsDEmitter) Gauge(name string, value float64, tags map[string]string) {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := make([]string, len(keys))
+	for i, key := range keys {
+		list[i] = key + ":" + tags[key]
+	}
+	e.send(name, value, "g", list)
+}
+
+// Close closes the connection to the agent
+func (e *StatsDEmitter) Close() error {
+	return e.conn.Close()
+}
+
+// send writes one metric as its own datagram
+func (e *StatsDEmitter) send(name string, value float64, kind string, tags []string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s:%g|%s", e.prefix, name, value, kind)
+	if all := append(e.tags[:len(e.tags):len(e.tags)], tags...); !e.plain && len(all) > 0 {
+		b.WriteString("|#")
+		for i, tag := range all {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(statsdTag(tag))
+		}
+	}
+	if _, err := e.conn.Write([]byte(b.String())); err != nil && e.logger != nil {
+		e.logger.Error("statsd: send %s: %v", name, err)
+	}
+}
+
+// statsdTag replaces the characters that delimit tags in DogStatsD
+var statsdTag = strings.NewReplacer("|", "_", ",", "_", "#", "_").Replace
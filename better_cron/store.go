@@ -0,0 +1,231 @@
+package better_cron
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Store is a key-value store for scheduler state that must outlive a
+// single run, such as dead-lettered runs. Keys are slash separated paths.
+type Store interface {
+	Put(key string, value []byte) error
+	Get(key string) ([]byte, bool, error)
+	Delete(key string) error
+	// List returns the sorted keys starting with prefix
+	List(prefix string) ([]string, error)
+}
+
+// ClaimStore is a Store that can write a key only if it is absent, so
+// instances sharing the store can claim work without racing each other.
+// MemoryStore and FileStore implement it; other stores are claimed with a
+// Get followed by a Put, which two instances may both pass.
+type ClaimStore interface {
+	Store
+	// PutIfAbsent stores value under key unless the key exists and reports
+	// whether it did
+	PutIfAbsent(key string, value []byte) (bool, error)
+}
+
+// claim writes value under key unless the key exists, atomically if the
+// store is a ClaimStore
+func claim(store Store, key string, value []byte) (bool, error) {
+	if cs, ok := store.(ClaimStore); ok {
+		return cs.PutIfAbsent(key, value)
+	}
+	if _, exists, err := store.Get(key); err != nil || exists {
+		return false, err
+	}
+	return true, store.Put(key, value)
+}
+
+// WithStore sets the store used for persistent scheduler state
+func WithStore(store Store) Option {
+	return func(ec *EnhancedCron) {
+		ec.store = store
+	}
+}
+
+// MemoryStore is a Store kept in process memory
+type MemoryStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string][]byte)}
+}
+
+// Put stores a copy of value under key
+func (s *MemoryStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// PutIfAbsent stores a copy of value under key unless the key exists
+func (s *MemoryStore) PutIfAbsent(key string, value []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[key]; ok {
+		return false, nil
+	}
+	s.data[key] = append([]byte(nil), value...)
+	return true, nil
+}
+
+// Get returns the value stored under key
+func (s *MemoryStore) Get(key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.data[key]
+	return append([]byte(nil), value...), ok, nil
+}
+
+// Delete removes key; deleting a missing key is not an error
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+// List returns the sorted keys starting with prefix
+func (s *MemoryStore) List(prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []string
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// FileStore is a Store that keeps one file per key in a directory, so state
+// survives process restarts
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore creates a FileStore in dir, creating the directory if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key))
+}
+
+// Put atomically writes value under key
+func (s *FileStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := s.writeTemp(value)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	return os.Rename(tmp, s.path(key))
+}
+
+// PutIfAbsent atomically writes value under key unless the key exists, also
+// against other processes sharing the directory
+func (s *FileStore) PutIfAbsent(key string, value []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := s.writeTemp(value)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp)
+	// Unlike a rename, a link fails if the key exists
+	err = os.Link(tmp, s.path(key))
+	if errors.Is(err, os.ErrExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// writeTemp writes value to a synced temporary file and returns its path
+func (s *FileStore) writeTemp(value []byte) (string, error) {
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// Get returns the value stored under key
+func (s *FileStore) Get(key string) ([]byte, bool, error) {
+	value, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Delete removes key; deleting a missing key is not an error
+func (s *FileStore) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List returns the sorted keys starting with prefix
+func (s *FileStore) List(prefix string) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".tmp-") {
+			continue
+		}
+		key, err := url.PathUnescape(e.Name())
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
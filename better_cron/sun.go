@@ -0,0 +1,132 @@
+package better_cron
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SunEvent selects the solar event a SunSchedule follows
+type SunEvent int
+
+const (
+	Sunrise SunEvent = iota
+	Sunset
+)
+
+// Convert SunEvent to string
+func (e SunEvent) String() string {
+	return [...]string{"sunrise", "sunset"}[e]
+}
+
+// SunSchedule fires at sunrise or sunset, shifted by Offset, at the given
+// coordinates. Days on which the event does not happen, such as polar night,
+// are skipped. Schedules can also be written as specs of the form
+// "@sunrise <lat> <lon> [offset]" or "@sunset <lat> <lon> [offset]".
+type SunSchedule struct {
+	Event     SunEvent
+	Latitude  float64 // degrees, north positive
+	Longitude float64 // degrees, east positive
+	Offset    time.Duration
+}
+
+// isSunSpec reports whether spec uses the @sunrise or @sunset descriptor
+func isSunSpec(spec string) bool {
+	return strings.HasPrefix(spec, "@sunrise") || strings.HasPrefix(spec, "@sunset")
+}
+
+// parseSunSpec parses "@sunrise <lat> <lon> [offset]"
+func parseSunSpec(spec string) (*SunSchedule, error) {
+	fields := strings.Fields(spec)
+	bad := func(format string, args ...interface{}) (*SunSchedule, error) {
+		return nil, fmt.Errorf("invalid schedule %q: %s", spec, fmt.Sprintf(format, args...))
+	}
+	if len(fields) < 3 || len(fields) > 4 {
+		return bad("expected %s <latitude> <longitude> [offset]", fields[0])
+	}
+
+	s := &SunSchedule{Event: Sunrise}
+	switch fields[0] {
+	case "@sunrise":
+	case "@sunset":
+		s.Event = Sunset
+	default:
+		return bad("unknown descriptor %q", fields[0])
+	}
+
+	var err error
+	if s.Latitude, err = strconv.ParseFloat(fields[1], 64); err != nil || math.Abs(s.Latitude) > 90 {
+		return bad("invalid latitude %q", fields[1])
+	}
+	if s.Longitude, err = strconv.ParseFloat(fields[2], 64); err != nil || math.Abs(s.Longitude) > 180 {
+		return bad("invalid longitude %q", fields[2])
+	}
+	if len(fields) == 4 {
+		if s.Offset, err = time.ParseDuration(fields[3]); err != nil {
+			return bad("invalid offset %q", fields[3])
+		}
+	}
+	return s, nil
+}
+
+// String formats the schedule as a spec accepted by AddJob
+func (s *SunSchedule) String() string {
+	spec := fmt.Sprintf("@%s %g %g", s.Event, s.Latitude, s.Longitude)
+	if s.Offset != 0 {
+		spec += " " + s.Offset.String()
+	}
+	return spec
+}
+
+// maxSunSearchDays bounds the search through polar night or midnight sun
+const maxSunSearchDays = 366
+
+// Next returns the first event time after t
+func (s *SunSchedule) Next(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, time.UTC)
+	// Start a day early: with a large offset yesterday's event can still be ahead
+	for i := -1; i <= maxSunSearchDays; i++ {
+		event, ok := s.eventOn(day.AddDate(0, 0, i))
+		if !ok {
+			continue
+		}
+		if event = event.Add(s.Offset); event.After(t) {
+			return event.In(t.Location())
+		}
+	}
+	return time.Time{}
+}
+
+// eventOn computes the event for the solar day around noon UTC using the
+// sunrise equation, reporting false if the sun does not rise or set that day
+func (s *SunSchedule) eventOn(noon time.Time) (time.Time, bool) {
+	const j2000 = 2451545.0
+	rad := math.Pi / 180
+
+	julianDay := float64(noon.Unix())/86400 + 2440587.5
+	n := math.Ceil(julianDay - j2000 + 0.0008)
+	meanNoon := n - s.Longitude/360
+
+	anomaly := math.Mod(357.5291+0.98560028*meanNoon, 360)
+	center := 1.9148*math.Sin(anomaly*rad) + 0.02*math.Sin(2*anomaly*rad) + 0.0003*math.Sin(3*anomaly*rad)
+	ecliptic := math.Mod(anomaly+center+180+102.9372, 360)
+	transit := j2000 + meanNoon + 0.0053*math.Sin(anomaly*rad) - 0.0069*math.Sin(2*ecliptic*rad)
+
+	declination := math.Asin(math.Sin(ecliptic*rad) * math.Sin(23.4397*rad))
+	latitude := s.Latitude * rad
+	cosHourAngle := (math.Sin(-0.833*rad) - math.Sin(latitude)*math.Sin(declination)) /
+		(math.Cos(latitude) * math.Cos(declination))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, false
+	}
+	hourAngle := math.Acos(cosHourAngle) / rad
+
+	event := transit - hourAngle/360
+	if s.Event == Sunset {
+		event = transit + hourAngle/360
+	}
+	seconds := (event - 2440587.5) * 86400
+	return time.Unix(0, int64(seconds*1e9)).UTC().Round(time.Second), true
+}
@@ -0,0 +1,137 @@
+package better_cron
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateData is what parameter templates and the templated fields of
+// Command and HTTPJob are expanded against, e.g.
+// {{(.ScheduledTime.AddDate 0 0 -1).Format "2006-01-02"}} for yesterday's
+// partition. Templates can also look up environment variables with
+// {{env "NAME"}}.
+type TemplateData struct {
+	Job   string
+	RunID string
+	// ScheduledTime is the slot the fire was due at, as
+	// JobMetadata.ScheduledTime
+	ScheduledTime time.Time
+	Trigger       string
+	// Params are the run's expanded parameters; parameter templates see
+	// only the ones that are not templates themselves
+	Params map[string]string
+}
+
+// templateFuncs are the functions available to templates
+var templateFuncs = template.FuncMap{
+	"env": os.Getenv,
+}
+
+// isTemplate reports whether text needs expanding
+func isTemplate(text string) bool {
+	return strings.Contains(text, "{{")
+}
+
+// parseTemplate parses text as a template
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// executeTemplate expands tmpl against data
+func executeTemplate(tmpl *template.Template, data TemplateData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// parseParams parses the job's parameters that are templates, so broken
+// templates fail the registration instead of every run
+func (cfg *jobConfig) parseParams() error {
+	cfg.paramTemplates = nil
+	for key, value := range cfg.params {
+		if !isTemplate(value) {
+			continue
+		}
+		tmpl, err := parseTemplate(key, value)
+		if err != nil {
+			return fmt.Errorf("param %s: %w", key, err)
+		}
+		if cfg.paramTemplates == nil {
+			cfg.paramTemplates = make(map[string]*template.Template)
+		}
+		cfg.paramTemplates[key] = tmpl
+	}
+	return nil
+}
+
+// expandParams resolves the job's parameters for one run
+func (cfg *jobConfig) expandParams(metadata *JobMetadata) (map[string]string, error) {
+	if len(cfg.paramTemplates) == 0 {
+		return cfg.params, nil
+	}
+
+	data := TemplateData{
+		Job:           metadata.Name,
+		RunID:         metadata.RunID,
+		ScheduledTime: metadata.ScheduledTime,
+		Trigger:       metadata.Trigger,
+		Params:        make(map[string]string, len(cfg.params)),
+	}
+	for key, value := range cfg.params {
+		if _, ok := cfg.paramTemplates[key]; !ok {
+			data.Params[key] = value
+		}
+	}
+
+	params := make(map[string]string, len(cfg.params))
+	for key, value := range data.Params {
+		params[key] = value
+	}
+	for key, tmpl := range cfg.paramTemplates {
+		value, err := executeTemplate(tmpl, data)
+		if err != nil {
+			return nil, fmt.Errorf("param %s: %w", key, err)
+		}
+		params[key] = value
+	}
+	return params, nil
+}
+
+// ExpandTemplate expands text as a template against the current run, for
+// job bodies that take templated settings. Text without "{{" and text
+// expanded outside a run are returned unchanged.
+func ExpandTemplate(ctx context.Context, text string) (string, error) {
+	id, ok := ctx.Value(identityKey).(runIdentity)
+	if !ok || !isTemplate(text) {
+		return text, nil
+	}
+	tmpl, err := parseTemplate("template", text)
+	if err != nil {
+		return "", err
+	}
+	return executeTemplate(tmpl, TemplateData{
+		Job:           id.job,
+		RunID:         id.runID,
+		ScheduledTime: id.scheduled,
+		Trigger:       id.trigger,
+		Params:        id.params,
+	})
+}
+
+// expandAll expands every string of values with ExpandTemplate
+func expandAll(ctx context.Context, values []string) ([]string, error) {
+	expanded := make([]string, len(values))
+	for i, value := range values {
+		var err error
+		if expanded[i], err = ExpandTemplate(ctx, value); err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
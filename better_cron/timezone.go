@@ -0,0 +1,43 @@
+package better_cron
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// WithTimezone evaluates the job's schedule in loc, so "0 0 9 * * *" fires at
+// 9am local time there. It takes precedence over a CRON_TZ= prefix in the spec.
+func WithTimezone(loc *time.Location) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.location = loc
+	}
+}
+
+// inLocation returns schedule evaluated in loc, copying cron's own spec
+// schedules rather than changing the caller's value. Other schedule types
+// are returned as they are.
+func inLocation(schedule cron.Schedule, loc *time.Location) cron.Schedule {
+	if s, ok := schedule.(*cron.SpecSchedule); ok && loc != nil {
+		copied := *s
+		copied.Location = loc
+		return &copied
+	}
+	return schedule
+}
+
+// scheduleLocation reports the zone a schedule is evaluated in. Interval
+// schedules such as "@every 1h" do not depend on a zone and report Local.
+func scheduleLocation(schedule cron.Schedule) *time.Location {
+	switch s := schedule.(type) {
+	case *cron.SpecSchedule:
+		if s.Location != nil {
+			return s.Location
+		}
+	case *quartzSchedule:
+		return s.base.Location
+	case *isoSchedule:
+		return s.start.Location()
+	}
+	return time.Local
+}
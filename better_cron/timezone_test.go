@@ -0,0 +1,35 @@
+package better_cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	from := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	want := time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		loc  *time.Location
+	}{
+		{"0 0 9 * * *", berlin},
+		{"CRON_TZ=Europe/Berlin 0 0 9 * * *", nil},
+		{"TZ=Europe/Berlin 0 0 9 * * *", nil},
+		// The job's zone wins over the spec's
+		{"CRON_TZ=America/New_York 0 0 9 * * *", berlin},
+	}
+	for _, tt := range tests {
+		schedule, err := parseSchedule(tt.spec, tt.loc)
+		if err != nil {
+			t.Fatalf("parseSchedule(%q): %v", tt.spec, err)
+		}
+		if next := schedule.Next(from); !next.Equal(want) {
+			t.Errorf("%q in %v fires at %v, want %v", tt.spec, tt.loc, next, want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package better_cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SpecIssue points at the part of a spec an error or warning is about
+type SpecIssue struct {
+	// Field is the cron field, e.g. "day-of-week", or empty for the whole spec
+	Field   string
+	Value   string
+	Message string
+}
+
+// String formats the issue as "field: message"
+func (i SpecIssue) String() string {
+	if i.Field == "" {
+		return i.Message
+	}
+	return i.Field + ": " + i.Message
+}
+
+// SpecError lists every error found in a spec
+type SpecError struct {
+	Spec   string
+	Issues []SpecIssue
+}
+
+func (e *SpecError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		issues[i] = issue.String()
+	}
+	return fmt.Sprintf("invalid spec %q: %s", e.Spec, strings.Join(issues, "; "))
+}
+
+// specField describes one field of a six-field cron spec
+type specField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var specFields = []specField{
+	{name: "second", max: 59},
+	{name: "minute", max: 59},
+	{name: "hour", max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day-of-week", max: 6, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// ValidateSpec checks a spec accepted by AddJob. Errors are returned as a
+// *SpecError naming each offending field; warnings flag specs that parse but
+// are probably not what was meant, such as one that fires every second.
+func ValidateSpec(spec string) (warnings []SpecIssue, err error) {
+	prefix, body := splitTimezone(spec)
+	if prefix != "" {
+		name := strings.TrimSpace(prefix[strings.IndexByte(prefix, '=')+1:])
+		if _, err := time.LoadLocation(name); err != nil {
+			return nil, &SpecError{spec, []SpecIssue{{Field: "timezone", Value: name, Message: err.Error()}}}
+		}
+	}
+
+	fields := strings.Fields(body)
+	if body == "" || strings.HasPrefix(body, "@") || isNaturalSpec(body) || isISOInterval(body) || hasQuartzDays(fields) {
+		if _, err := parseSchedule(spec, nil); err != nil {
+			return nil, &SpecError{spec, []SpecIssue{{Message: trimSpecPrefix(err.Error(), body)}}}
+		}
+		return nil, nil
+	}
+
+	if len(fields) != len(specFields) {
+		msg := fmt.Sprintf("expected %d fields, found %d", len(specFields), len(fields))
+		if len(fields) == len(specFields)-1 {
+			msg += `; specs include a seconds field, e.g. "0 ` + body + `"`
+		}
+		return nil, &SpecError{spec, []SpecIssue{{Message: msg}}}
+	}
+
+	var issues []SpecIssue
+	sets := make([][]bool, len(specFields))
+	for i, f := range specFields {
+		var w []SpecIssue
+		var errs []SpecIssue
+		sets[i], w, errs = f.check(fields[i])
+		warnings = append(warnings, w...)
+		issues = append(issues, errs...)
+	}
+	if len(issues) > 0 {
+		return warnings, &SpecError{spec, issues}
+	}
+	if _, err := parseSchedule(spec, nil); err != nil {
+		return warnings, &SpecError{spec, []SpecIssue{{Message: err.Error()}}}
+	}
+
+	if fields[0] == "*" {
+		warnings = append(warnings, SpecIssue{Field: "second", Value: "*", Message: "fires every second"})
+	}
+	if !isWildcard(fields[3]) && !isWildcard(fields[5]) {
+		warnings = append(warnings, SpecIssue{Message: "day-of-month and day-of-week are both set; the job fires on days matching either"})
+	}
+	if isWildcard(fields[5]) && neverInMonth(sets[3], sets[4]) {
+		warnings = append(warnings, SpecIssue{Field: "day-of-month", Value: fields[3], Message: "no selected month has the selected days, so the job never fires"})
+	}
+	return warnings, nil
+}
+
+// check parses one field into the set of values it matches
+func (f specField) check(expr string) (set []bool, warnings, errs []SpecIssue) {
+	set = make([]bool, f.max+1)
+	bad := func(value, format string, args ...interface{}) {
+		errs = append(errs, SpecIssue{Field: f.name, Value: value, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, part := range strings.Split(expr, ",") {
+		if part == "" {
+			bad(expr, "empty list item")
+			continue
+		}
+
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepExpr)
+			if err != nil || n < 1 {
+				bad(part, "invalid step %q", stepExpr)
+				continue
+			}
+			if n > f.max-f.min {
+				warnings = append(warnings, SpecIssue{Field: f.name, Value: part,
+					Message: fmt.Sprintf("step %d exceeds the field's range, only the first value matches", n)})
+			}
+			step = n
+		}
+
+		start, end := f.min, f.max
+		if rangeExpr != "*" && rangeExpr != "?" {
+			lo, hi, isRange := strings.Cut(rangeExpr, "-")
+			var ok bool
+			if start, ok = f.value(lo, bad); !ok {
+				continue
+			}
+			end = start
+			if isRange {
+				if end, ok = f.value(hi, bad); !ok {
+					continue
+				}
+			} else if hasStep {
+				end = f.max
+			}
+			if start > end {
+				bad(rangeExpr, "range %s starts after it ends", rangeExpr)
+				continue
+			}
+		}
+		for v := start; v <= end; v += step {
+			set[v] = true
+		}
+	}
+	return set, warnings, errs
+}
+
+// value parses a single number or name, reporting out of range values
+func (f specField) value(s string, bad func(value, format string, args ...interface{})) (int, bool) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, true
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		bad(s, "invalid value %q", s)
+		return 0, false
+	}
+	if v < f.min || v > f.max {
+		bad(s, "value %d out of range (%d-%d)", v, f.min, f.max)
+		return 0, false
+	}
+	return v, true
+}
+
+// trimSpecPrefix drops the "invalid schedule %q: " lead-in of a parse error,
+// which SpecError already provides
+func trimSpecPrefix(msg, spec string) string {
+	for _, kind := range []string{"spec", "schedule", "interval"} {
+		msg = strings.TrimPrefix(msg, fmt.Sprintf("invalid %s %q: ", kind, spec))
+	}
+	return msg
+}
+
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// neverInMonth reports whether none of the selected months has any of the
+// selected days
+func neverInMonth(days, months []bool) bool {
+	longest := [...]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	for m := 1; m <= 12; m++ {
+		if !months[m] {
+			continue
+		}
+		for d := 1; d <= longest[m]; d++ {
+			if days[d] {
+				return false
+			}
+		}
+	}
+	return true
+}
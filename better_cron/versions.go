@@ -0,0 +1,193 @@
+package better_cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const versionPrefix = "versions/"
+
+// JobVersion is one revision of a job's definition. With a store
+// configured, a version is recorded every time the job is registered,
+// updated or removed.
+type JobVersion struct {
+	Job     string    `json:"job"`
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	// Action is AuditRegistered, AuditUpdated or AuditRemoved
+	Action string `json:"action"`
+	Actor  string `json:"actor"`
+	// Settings describes the schedule and options of the job; it is empty
+	// once the job is removed
+	Settings map[string]string `json:"settings,omitempty"`
+	// Changes lists the settings that differ from the previous version
+	Changes []SettingChange `json:"changes,omitempty"`
+}
+
+// SettingChange is one setting that changed between two versions; Old or
+// New is empty when the setting was added or dropped
+type SettingChange struct {
+	Setting string `json:"setting"`
+	Old     string `json:"old,omitempty"`
+	New     string `json:"new,omitempty"`
+}
+
+// settings describes the parts of a job's configuration that can be
+// compared between versions. Job bodies, sinks and policies given as
+// functions cannot be, and are left out.
+func (cfg *jobConfig) settings(spec string) map[string]string {
+	s := map[string]string{"spec": spec}
+	set := func(key string, value interface{}, isSet bool) {
+		if isSet {
+			s[key] = fmt.Sprint(value)
+		}
+	}
+
+	set("tags", strings.Join(cfg.tags, ","), len(cfg.tags) > 0)
+	set("params", joinParams(cfg.params), len(cfg.params) > 0)
+	set("idempotency", true, cfg.idempotencyKey != nil)
+	set("fingerprint", true, cfg.fingerprint != nil)
+	set("timezone", cfg.location, cfg.location != nil)
+	set("timeout", cfg.timeout, cfg.timeout > 0)
+	set("grace_period", cfg.grace, cfg.grace > 0)
+	set("run_on_start", cfg.runOnStart, cfg.runOnStart)
+	set("max_runs", cfg.maxRuns, cfg.maxRuns > 0)
+	set("end_at", cfg.endAt.Format(time.RFC3339), !cfg.endAt.IsZero())
+	set("max_attempts", cfg.maxAttempts, cfg.maxAttempts > 0)
+	set("overlap", [...]string{"allow", "skip", "queue"}[cfg.overlap], cfg.overlap != OverlapAllow)
+	set("queue_depth", cfg.queueDepth, cfg.overlap == OverlapQueue)
+	set("max_instances", cfg.maxInstances, cfg.maxInstances > 0)
+	set("jitter", cfg.jitter, cfg.jitter > 0)
+	set("load_smoothing", "off", cfg.noSmoothing)
+	set("allowed_windows", joinWindows(cfg.allowed), len(cfg.allowed) > 0)
+	set("blackout_windows", joinWindows(cfg.blackouts), len(cfg.blackouts) > 0)
+	set("breaker", fmt.Sprintf("%d failures, %v cooldown", cfg.breakerThreshold, cfg.breakerCooldown), cfg.breakerThreshold > 0)
+	set("budget", fmt.Sprintf("%v per %v", cfg.budget, cfg.budgetWindow), cfg.budget > 0)
+	set("sla", fmt.Sprintf("%s, warning %v", cfg.slaSpec, cfg.slaWarning), cfg.slaSpec != "")
+	set("heartbeat", cfg.heartbeat, cfg.heartbeat > 0)
+	set("rerun_interrupted", cfg.rerunInterrupted, cfg.rerunInterrupted)
+	set("failure_threshold", cfg.failureThreshold, cfg.failureThreshold > 0)
+	set("schedule_backoff", cfg.backoffMax, cfg.backoffMax > 0)
+	set("upstreams", strings.Join(cfg.upstreams, ","), len(cfg.upstreams) > 0)
+	set("tight_schedule", fmt.Sprintf("%v, %s", cfg.tightRatio, [...]string{"warn", "skip", "delay"}[cfg.tightPolicy]), cfg.tightRatio > 0)
+	return s
+}
+
+func joinWindows(windows []TimeWindow) string {
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		parts[i] = w.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// recordChange writes a registration, update or removal of a job to the
+// audit log and its version history; cfg is nil for a removal
+func (ec *EnhancedCron) recordChange(action, name, actor, spec string, cfg *jobConfig) {
+	if ec.readOnly {
+		return
+	}
+	ec.record(AuditRecord{Action: action, Job: name, Actor: actor, Detail: spec})
+	if ec.store == nil {
+		return
+	}
+
+	var settings map[string]string
+	if cfg != nil {
+		settings = cfg.settings(spec)
+	}
+	if err := ec.storeVersion(action, name, actor, settings); err != nil {
+		ec.logger.Error("failed to record version of job %s: %v", name, err)
+	}
+}
+
+func (ec *EnhancedCron) storeVersion(action, name, actor string, settings map[string]string) error {
+	ec.versionMu.Lock()
+	defer ec.versionMu.Unlock()
+
+	versions, err := ec.GetJobVersions(name)
+	if err != nil {
+		return err
+	}
+	version := JobVersion{
+		Job:      name,
+		Version:  1,
+		Time:     ec.clock.Now(),
+		Action:   action,
+		Actor:    actor,
+		Settings: settings,
+	}
+	var previous map[string]string
+	if n := len(versions); n > 0 {
+		version.Version = versions[n-1].Version + 1
+		previous = versions[n-1].Settings
+	}
+	version.Changes = diffSettings(previous, settings)
+
+	data, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+	return ec.store.Put(versionKey(name, version.Version), data)
+}
+
+// diffSettings returns the settings that differ, sorted by name
+func diffSettings(old, new map[string]string) []SettingChange {
+	var changes []SettingChange
+	for key, value := range new {
+		if old[key] != value {
+			changes = append(changes, SettingChange{Setting: key, Old: old[key], New: value})
+		}
+	}
+	for key, value := range old {
+		if _, ok := new[key]; !ok {
+			changes = append(changes, SettingChange{Setting: key, Old: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Setting < changes[j].Setting })
+	return changes
+}
+
+func versionKey(name string, version int) string {
+	return fmt.Sprintf("%s%s/%08d", versionPrefix, name, version)
+}
+
+// GetJobVersions returns the recorded versions of a job, oldest first.
+// Versions of removed jobs are kept, so a job that was added again
+// continues its numbering.
+func (ec *EnhancedCron) GetJobVersions(name string) ([]JobVersion, error) {
+	if ec.store == nil {
+		return nil, fmt.Errorf("no store configured")
+	}
+
+	prefix := versionPrefix + name + "/"
+	keys, err := ec.store.List(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []JobVersion
+	for _, key := range keys {
+		// Skip the versions of jobs whose name continues with a slash
+		if _, err := strconv.Atoi(strings.TrimPrefix(key, prefix)); err != nil {
+			continue
+		}
+		data, ok, err := ec.store.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		var version JobVersion
+		if err := json.Unmarshal(data, &version); err != nil {
+			return nil, fmt.Errorf("decode job version %s: %w", key, err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
@@ -0,0 +1,68 @@
+package better_cron
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// WatchJobsFile reloads a job definition file whenever its modification time
+// changes (checked every interval) or the process receives SIGHUP. An
+// interval of 0 only reloads on SIGHUP. Invalid files are logged and the
+// running jobs are kept. Watching stops at shutdown or when stop is called.
+func (ec *EnhancedCron) WatchJobsFile(path string, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	lastMod := modTime(path)
+	reload := func(trigger string) {
+		lastMod = modTime(path)
+		if err := ec.loadJobsFile(path, "watch:"+trigger); err != nil {
+			ec.logger.Error("reload of %s after %s failed, keeping current jobs: %v", path, trigger, err)
+		}
+	}
+
+	go func() {
+		defer signal.Stop(hup)
+
+		var tick <-chan time.Time
+		if interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ec.stoppingContext().Done():
+				return
+			case <-hup:
+				reload("SIGHUP")
+			case <-tick:
+				if mod := modTime(path); !mod.Equal(lastMod) {
+					reload("file change")
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// modTime returns the modification time of path, or the zero time if it
+// cannot be read
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
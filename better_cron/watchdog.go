@@ -0,0 +1,98 @@
+package better_cron
+
+import (
+	"bytes"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithHungJobTimeout sets how long a run may keep going after its context
+// ends, through a timeout, cancellation or shutdown, before it is treated
+// as hung. A hung run has the stacks of its goroutines logged and is
+// tracked as orphaned while the scheduler moves on. The default is 30
+// seconds.
+func WithHungJobTimeout(timeout time.Duration) Option {
+	return func(ec *EnhancedCron) {
+		ec.hungTimeout = timeout
+	}
+}
+
+// awaitCancelled waits for a run whose context has ended to return. If it
+// is still going after the hung timeout, the stacks it is stuck in are
+// logged and returned, and hung is set.
+func (ec *EnhancedCron) awaitCancelled(wg *sync.WaitGroup, metadata *JobMetadata) (stacks []byte, hung bool) {
+	timer := time.NewTimer(ec.hungTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-waitWithTimeout(wg, 0):
+		return nil, false
+	case <-timer.C:
+	}
+
+	stacks = runStacks(metadata.RunID)
+	ec.logger.Error("job %s run %s still running %v after its context ended, orphaning it:\n%s",
+		metadata.Name, metadata.RunID, ec.hungTimeout, stacks)
+	return stacks, true
+}
+
+// orphanedRun returns the record of a hung run for the scheduler to report
+// and track. The run's goroutine keeps writing to its own metadata, so the
+// record only copies what was set before the run started.
+func orphanedRun(metadata *JobMetadata, stacks []byte) *JobMetadata {
+	return &JobMetadata{
+		ID:        metadata.ID,
+		RunID:     metadata.RunID,
+		Name:      metadata.Name,
+		StartTime: metadata.StartTime,
+		Stack:     stacks,
+		Orphaned:  true,
+	}
+}
+
+// trackOrphan lists a hung run in GetOrphanedJobs until it returns
+func (ec *EnhancedCron) trackOrphan(wg *sync.WaitGroup, metadata *JobMetadata) {
+	orphan := *metadata
+	ec.orphans.Store(orphan.RunID, &orphan)
+	go func() {
+		wg.Wait()
+		ec.orphans.Delete(orphan.RunID)
+		ec.completeJournal(orphan.RunID)
+		ec.logger.Info("orphaned run %s of job %s returned", orphan.RunID, orphan.Name)
+	}()
+}
+
+// runStacks returns the goroutine profile records labeled with the run ID,
+// i.e. the run's own goroutine and any it started
+func runStacks(runID string) []byte {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return []byte(fmt.Sprintf("goroutine profile unavailable: %v", err))
+	}
+
+	label := fmt.Sprintf("%q:%q", "run_id", runID)
+	var records []string
+	for _, record := range strings.Split(buf.String(), "\n\n") {
+		if strings.Contains(record, label) {
+			records = append(records, strings.TrimSpace(record))
+		}
+	}
+	if len(records) == 0 {
+		return []byte("no goroutines found for run")
+	}
+	return []byte(strings.Join(records, "\n\n"))
+}
+
+// GetOrphanedJobs returns the runs that were given up on as hung and have
+// not returned yet
+func (ec *EnhancedCron) GetOrphanedJobs() []*JobMetadata {
+	var jobs []*JobMetadata
+	ec.orphans.Range(func(key, value interface{}) bool {
+		jobs = append(jobs, value.(*JobMetadata))
+		return true
+	})
+	return jobs
+}
@@ -0,0 +1,178 @@
+package better_cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeWindow is a recurring daily span of time, optionally limited to some
+// days of the week. A window whose End is before its Start wraps past
+// midnight, and Days then refers to the day the window opens. One whose
+// End equals its Start, such as "00:00-00:00", spans the whole day.
+type TimeWindow struct {
+	Days  []time.Weekday
+	Start time.Duration
+	End   time.Duration
+
+	// Location the window is evaluated in; nil uses the job's time zone
+	Location *time.Location
+}
+
+// ParseTimeWindow parses a window such as "00:00-02:00" or
+// "Mon-Fri 08:00-18:00". Days may be a range or a comma separated list.
+func ParseTimeWindow(s string) (TimeWindow, error) {
+	var w TimeWindow
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+	case 2:
+		days, err := parseWeekdays(fields[0])
+		if err != nil {
+			return w, fmt.Errorf("window %q: %w", s, err)
+		}
+		w.Days = days
+	default:
+		return w, fmt.Errorf("window %q: expected [days] HH:MM-HH:MM", s)
+	}
+
+	bounds := strings.SplitN(fields[len(fields)-1], "-", 2)
+	if len(bounds) != 2 {
+		return w, fmt.Errorf("window %q: expected HH:MM-HH:MM", s)
+	}
+	var err error
+	if w.Start, err = parseClock(bounds[0]); err != nil {
+		return w, fmt.Errorf("window %q: %w", s, err)
+	}
+	if w.End, err = parseClock(bounds[1]); err != nil {
+		return w, fmt.Errorf("window %q: %w", s, err)
+	}
+	return w, nil
+}
+
+// Contains reports whether t falls inside the window
+func (w TimeWindow) Contains(t time.Time) bool {
+	if w.Location != nil {
+		t = t.In(w.Location)
+	}
+	// The wall clock, not the time since midnight, which is off by the
+	// shift on days the clocks change
+	hour, min, sec := t.Clock()
+	offset := time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+
+	if w.Start == w.End {
+		return w.onDay(t.Weekday())
+	}
+	if w.Start < w.End {
+		return w.onDay(t.Weekday()) && offset >= w.Start && offset < w.End
+	}
+	return (w.onDay(t.Weekday()) && offset >= w.Start) ||
+		(w.onDay((t.Weekday()+6)%7) && offset < w.End)
+}
+
+// String formats the window in the form accepted by ParseTimeWindow
+func (w TimeWindow) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	span := clock(w.Start) + "-" + clock(w.End)
+	if len(w.Days) == 0 {
+		return span
+	}
+	days := make([]string, len(w.Days))
+	for i, d := range w.Days {
+		days[i] = d.String()[:3]
+	}
+	return strings.Join(days, ",") + " " + span
+}
+
+func (w TimeWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// WithAllowedWindows only lets the job run during one of the given windows.
+// Fires outside all of them are skipped.
+func WithAllowedWindows(windows ...TimeWindow) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.allowed = append(cfg.allowed, windows...)
+	}
+}
+
+// WithBlackoutWindows skips every fire that lands inside one of the windows,
+// e.g. during nightly backups
+func WithBlackoutWindows(windows ...TimeWindow) JobOption {
+	return func(cfg *jobConfig) {
+		cfg.blackouts = append(cfg.blackouts, windows...)
+	}
+}
+
+// checkWindows reports whether the job may run at t, and if not, why
+func (entry *jobEntry) checkWindows(t time.Time) (bool, string) {
+	t = t.In(entry.location)
+	for _, w := range entry.cfg.blackouts {
+		if w.Contains(t) {
+			return false, "inside blackout window " + w.String()
+		}
+	}
+	if len(entry.cfg.allowed) == 0 {
+		return true, ""
+	}
+	for _, w := range entry.cfg.allowed {
+		if w.Contains(t) {
+			return true, ""
+		}
+	}
+	return false, "outside allowed windows"
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWeekdays parses "Mon-Fri", "Sat,Sun" or a mix of both
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		first, ok := weekdayNames[strings.ToLower(bounds[0])]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", bounds[0])
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, ok = weekdayNames[strings.ToLower(bounds[1])]; !ok {
+				return nil, fmt.Errorf("unknown weekday %q", bounds[1])
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseClock parses HH:MM into an offset from midnight; 24:00 is allowed as
+// the end of the day
+func parseClock(s string) (time.Duration, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(s, "%d:%d", &hour, &minute); err != nil || len(s) != 5 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
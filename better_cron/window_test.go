@@ -0,0 +1,80 @@
+package better_cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeWindowContains(t *testing.T) {
+	// Tuesday
+	day := func(d, hour, min int) time.Time {
+		return time.Date(2026, 3, d, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		window string
+		at     time.Time
+		want   bool
+	}{
+		{"09:00-17:00", day(10, 12, 0), true},
+		{"09:00-17:00", day(10, 9, 0), true},
+		{"09:00-17:00", day(10, 17, 0), false},
+		{"09:00-17:00", day(10, 8, 59), false},
+		{"22:00-06:00", day(10, 23, 0), true},
+		{"22:00-06:00", day(10, 5, 59), true},
+		{"22:00-06:00", day(10, 12, 0), false},
+		{"00:00-24:00", day(10, 23, 59), true},
+		{"00:00-00:00", day(10, 0, 0), true},
+		{"00:00-00:00", day(10, 15, 30), true},
+		{"Mon-Fri 09:00-17:00", day(14, 12, 0), false},
+		{"Sat,Sun 00:00-00:00", day(14, 12, 0), true},
+		{"Sat,Sun 00:00-00:00", day(16, 12, 0), false},
+		// A wrapping window belongs to the day it opens
+		{"Fri 22:00-02:00", day(14, 1, 0), true},
+		{"Fri 22:00-02:00", day(15, 1, 0), false},
+	}
+	for _, tt := range tests {
+		w, err := ParseTimeWindow(tt.window)
+		if err != nil {
+			t.Fatalf("ParseTimeWindow(%q): %v", tt.window, err)
+		}
+		if got := w.Contains(tt.at); got != tt.want {
+			t.Errorf("%q contains %s = %v, want %v", tt.window, tt.at.Format("Mon 15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestTimeWindowContainsDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	w, err := ParseTimeWindow("08:00-09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Location = berlin
+
+	// The clocks go forward at 02:00 on 2026-03-29 and back at 03:00 on
+	// 2026-10-25, so those days are 23 and 25 hours long
+	for _, d := range []int{29, 25} {
+		month := time.March
+		if d == 25 {
+			month = time.October
+		}
+		if !w.Contains(time.Date(2026, month, d, 8, 30, 0, 0, berlin)) {
+			t.Errorf("08:30 on %s %d is outside the window", month, d)
+		}
+		if w.Contains(time.Date(2026, month, d, 9, 30, 0, 0, berlin)) {
+			t.Errorf("09:30 on %s %d is inside the window", month, d)
+		}
+	}
+}
+
+func TestParseTimeWindowErrors(t *testing.T) {
+	for _, s := range []string{"", "09:00", "9:00-17:00", "Funday 09:00-17:00", "09:00-17:60", "Mon 09:00-17:00 extra"} {
+		if _, err := ParseTimeWindow(s); err == nil {
+			t.Errorf("ParseTimeWindow(%q) succeeded, want an error", s)
+		}
+	}
+}
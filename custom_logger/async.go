@@ -0,0 +1,125 @@
+package custom_logger
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrWriterClosed is returned by writes to a closed AsyncWriter
+var ErrWriterClosed = errors.New("async writer closed")
+
+// AsyncWriter hands writes to a background goroutine through a bounded
+// queue, so a slow disk or network output never blocks the caller. When the
+// queue is full, writes are dropped and counted rather than waiting. Use it
+// as the writer of NewLogger or of an Output.
+type AsyncWriter struct {
+	out   io.Writer
+	queue chan asyncWrite
+	done  chan struct{}
+
+	mu      sync.RWMutex
+	closed  bool
+	dropped atomic.Uint64
+	err     atomic.Pointer[error]
+}
+
+// asyncWrite is a queued line, or a flush marker when flushed is set
+type asyncWrite struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+// NewAsyncWriter starts a writer queueing up to size writes ahead of out
+func NewAsyncWriter(out io.Writer, size int) *AsyncWriter {
+	if size < 1 {
+		size = 1
+	}
+	w := &AsyncWriter{
+		out:   out,
+		queue: make(chan asyncWrite, size),
+		done:  make(chan struct{}),
+	}
+	go w.flusher()
+	return w
+}
+
+// Write queues a copy of p. It never blocks: if the queue is full the write
+// is dropped, which Dropped reports.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	select {
+	case w.queue <- asyncWrite{data: append([]byte(nil), p...)}:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Flush waits until every write queued before it has reached the output and
+// returns the last error the output reported, if any
+func (w *AsyncWriter) Flush() error {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return ErrWriterClosed
+	}
+	flushed := make(chan struct{})
+	w.queue <- asyncWrite{flushed: flushed}
+	w.mu.RUnlock()
+
+	<-flushed
+	return w.lastError()
+}
+
+// Close writes out everything still queued, stops the background goroutine
+// and closes the output if it is an io.Closer
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	if closer, ok := w.out.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return w.lastError()
+}
+
+// Dropped returns how many writes were discarded because the queue was full
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+func (w *AsyncWriter) flusher() {
+	defer close(w.done)
+	for write := range w.queue {
+		if write.flushed != nil {
+			close(write.flushed)
+			continue
+		}
+		if _, err := w.out.Write(write.data); err != nil {
+			w.err.Store(&err)
+		}
+	}
+}
+
+func (w *AsyncWriter) lastError() error {
+	if err := w.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package custom_logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// callerLevel selects how much of the call site entries carry
+type callerLevel int
+
+const (
+	callerOff callerLevel = iota
+	callerLine
+	callerFunction
+)
+
+// wrapperPrefixes are the functions skipped when looking for the call site:
+// this package and the logger wrappers of this module
+var wrapperPrefixes = []string{
+	"cron_test/custom_logger.",
+	"cron_test/better_cron.(*runLogger).",
+}
+
+// WithCaller records the file:line of the code that logged each entry
+func WithCaller() Option {
+	return func(l *Logger) {
+		if l.caller < callerLine {
+			l.caller = callerLine
+		}
+	}
+}
+
+// WithCallerFunction records the calling function's short name as well as
+// its file:line
+func WithCallerFunction() Option {
+	return func(l *Logger) {
+		l.caller = callerFunction
+	}
+}
+
+// fillCaller sets the entry's call site to the first frame outside the
+// logger and its wrappers
+func (l *Logger) fillCaller(entry *Entry) {
+	if l.caller == callerOff || entry.Caller != "" {
+		return
+	}
+
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isWrapper(frame.Function) {
+			l.setCaller(entry, frame)
+			return
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// setCaller records frame as the entry's call site
+func (l *Logger) setCaller(entry *Entry, frame runtime.Frame) {
+	entry.Caller = shortPath(frame.File) + ":" + strconv.Itoa(frame.Line)
+	if l.caller == callerFunction {
+		entry.Function = shortFunction(frame.Function)
+	}
+}
+
+func isWrapper(function string) bool {
+	for _, prefix := range wrapperPrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// shortPath keeps the last directory and the file name, e.g. jobs/sync.go
+func shortPath(file string) string {
+	dir, name := filepath.Split(file)
+	return filepath.Join(filepath.Base(dir), name)
+}
+
+// shortFunction strips the import path, e.g. better_cron.(*EnhancedCron).fire
+func shortFunction(function string) string {
+	if i := strings.LastIndex(function, "/"); i >= 0 {
+		return function[i+1:]
+	}
+	return function
+}
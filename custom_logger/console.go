@@ -0,0 +1,78 @@
+package custom_logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences used by ConsoleFormatter
+const (
+	ansiReset = "\x1b[0m"
+	ansiDim   = "\x1b[2m"
+)
+
+// levelColors holds the ANSI color of each level
+var levelColors = [...]string{
+	DEBUG:   "\x1b[90m",
+	INFO:    "\x1b[36m",
+	WARNING: "\x1b[33m",
+	ERROR:   "\x1b[31m",
+	FATAL:   "\x1b[1;35m",
+}
+
+// ConsoleFormatter renders entries for reading in a terminal: time, level
+// padded to a fixed width so messages line up, the call site if recorded,
+// the message and sorted key=value fields. With Color set, levels are colored and fields dimmed.
+type ConsoleFormatter struct {
+	Color bool
+}
+
+// NewConsoleFormatter returns a ConsoleFormatter that colors its output
+// when w is a terminal and the NO_COLOR environment variable is unset
+func NewConsoleFormatter(w io.Writer) ConsoleFormatter {
+	return ConsoleFormatter{Color: isTerminal(w) && os.Getenv("NO_COLOR") == ""}
+}
+
+// Format implements Formatter
+func (f ConsoleFormatter) Format(entry Entry) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString(entry.Time.Format("15:04:05.000"))
+	b.WriteByte(' ')
+
+	level := fmt.Sprintf("%-7s", entry.Level)
+	if f.Color {
+		level = levelColors[entry.Level] + level + ansiReset
+	}
+	b.WriteString(level)
+	b.WriteByte(' ')
+	if site := callSite(entry); site != "" {
+		if f.Color {
+			site = ansiDim + site + ansiReset
+		}
+		b.WriteString(site)
+		b.WriteByte(' ')
+	}
+	b.WriteString(entry.Message)
+
+	for _, key := range sortedKeys(entry.Fields) {
+		field := key + "=" + fieldValue(entry.Fields[key])
+		if f.Color {
+			field = ansiDim + field + ansiReset
+		}
+		b.WriteByte(' ')
+		b.WriteString(field)
+	}
+	return []byte(b.String()), nil
+}
+
+// isTerminal reports whether w is a character device such as a TTY
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -1,6 +1,7 @@
 package custom_logger
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -26,39 +27,178 @@ func (l LogLevel) String() string {
 
 // Logger represents a custom logger
 type Logger struct {
-	level  LogLevel
-	output io.Writer
+	// level is the minimum level; loggers derived with WithFields share it
+	level      *levelVar
+	name       string
+	components *components
+	outputs    []Output
+	formatter  Formatter
+	fields     map[string]interface{}
+	dedup      *deduplicator
+	hooks      *hookSet
+	caller     callerLevel
+	fatal      *fatalHandler
+}
+
+// Option represents a configuration option for Logger
+type Option func(*Logger)
+
+// WithFormatter sets how entries are rendered by outputs without their own
+// formatter; the default is TextFormatter
+func WithFormatter(formatter Formatter) Option {
+	return func(l *Logger) {
+		l.formatter = formatter
+	}
 }
 
 // NewLogger creates a new Logger with the specified minimum log level
-func NewLogger(level LogLevel, output io.Writer) *Logger {
+func NewLogger(level LogLevel, output io.Writer, opts ...Option) *Logger {
 	if output == nil {
 		output = os.Stdout
 	}
-	return &Logger{
-		level:  level,
-		output: output,
+	return newLogger(level, []Output{{Writer: output}}, opts)
+}
+
+func newLogger(level LogLevel, outputs []Output, opts []Option) *Logger {
+	l := &Logger{
+		level:     newLevelVar(level),
+		outputs:   outputs,
+		formatter: TextFormatter{},
+		hooks:     &hookSet{},
+		fatal:     &fatalHandler{action: ExitOnFatal},
+	}
+	l.components = &components{levels: map[string]*levelVar{rootComponent: l.level}}
+	for _, opt := range opts {
+		opt(l)
 	}
+	return l
+}
+
+// WithFields returns a logger that attaches fields to every entry, on top
+// of any fields this logger already carries
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	child := *l
+	child.fields = copyFields(l.fields)
+	for key, value := range fields {
+		child.fields[key] = value
+	}
+	return &child
+}
+
+// With returns a logger that attaches alternating keys and values to every
+// entry, e.g. logger.With("job", name, "attempt", 2). A value without a key
+// is kept under "!BADKEY", as slog does.
+func (l *Logger) With(keyvals ...interface{}) *Logger {
+	fields := make(map[string]interface{}, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 == len(keyvals) {
+			fields["!BADKEY"] = keyvals[i]
+			break
+		}
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		fields[key] = keyvals[i+1]
+	}
+	return l.WithFields(fields)
 }
 
 // log formats and writes a log message if the log level is sufficient
 func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
-	if level < l.level {
+	l.logMessage(level, fmt.Sprintf(format, args...), nil)
+}
+
+// logMessage writes a message and, for FATAL, runs the fatal action with
+// err, or with the message as an error when err is nil
+func (l *Logger) logMessage(level LogLevel, message string, err error) {
+	if !l.Enabled(level) {
 		return
 	}
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	prefix := fmt.Sprintf("[%s] [%s] ", timestamp, level)
-	message := fmt.Sprintf(format, args...)
-
-	fmt.Fprintf(l.output, "%s%s\n", prefix, message)
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+		Fields:  l.fields,
+	}
+	l.fillCaller(&entry)
+	l.write(entry)
 
-	// If it's a fatal message, exit the program
 	if level == FATAL {
-		os.Exit(1)
+		if err == nil {
+			err = errors.New(message)
+		}
+		l.fatal.get()(err)
 	}
 }
 
+// Enabled reports whether entries at level pass the minimum level
+func (l *Logger) Enabled(level LogLevel) bool {
+	return level >= l.level.get()
+}
+
+// LogEntry writes an entry built elsewhere, adding the logger's fields. It
+// is meant for bridges from other logging libraries: each output still
+// applies its own minimum level, but FATAL entries never exit.
+func (l *Logger) LogEntry(entry Entry) error {
+	if len(l.fields) > 0 {
+		fields := copyFields(l.fields)
+		for key, value := range entry.Fields {
+			fields[key] = value
+		}
+		entry.Fields = fields
+	}
+	return l.write(entry)
+}
+
+// write passes the entry through deduplication, if enabled, to the outputs
+func (l *Logger) write(entry Entry) error {
+	l.hooks.fire(entry)
+	if l.dedup != nil && !l.dedup.admit(l, entry) {
+		return nil
+	}
+	return l.output(entry)
+}
+
+// output renders the entry as one line on every output whose level admits it
+func (l *Logger) output(entry Entry) error {
+	var errs []error
+	for _, out := range l.outputs {
+		if entry.Level < out.Level {
+			continue
+		}
+		if ew, ok := out.Writer.(EntryWriter); ok {
+			if err := ew.WriteEntry(entry); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		formatter := out.Formatter
+		if formatter == nil {
+			formatter = l.formatter
+		}
+		line, err := formatter.Format(entry)
+		if err != nil {
+			line = []byte(fmt.Sprintf("failed to format log entry: %v", err))
+		}
+		if _, err := out.Writer.Write(append(line, '\n')); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// InfoFields logs an info message carrying extra fields
+func (l *Logger) InfoFields(fields map[string]interface{}, format string, args ...interface{}) {
+	l.WithFields(fields).log(INFO, format, args...)
+}
+
+// ErrorFields logs an error message carrying extra fields
+func (l *Logger) ErrorFields(fields map[string]interface{}, format string, args ...interface{}) {
+	l.WithFields(fields).log(ERROR, format, args...)
+}
+
 // Debug logs a debug message
 func (l *Logger) Debug(format string, args ...interface{}) {
 	l.log(DEBUG, format, args...)
@@ -79,7 +219,15 @@ func (l *Logger) Error(format string, args ...interface{}) {
 	l.log(ERROR, format, args...)
 }
 
-// Fatal logs a fatal message and exits the program
+// Fatal logs a fatal message and runs the fatal action, which exits the
+// program unless WithFatalAction says otherwise
 func (l *Logger) Fatal(format string, args ...interface{}) {
 	l.log(FATAL, format, args...)
 }
+
+// FatalErr logs err as a fatal message and hands it to the fatal action,
+// e.g. one from better_cron's ShutdownOnFatal that shuts the scheduler
+// down gracefully instead of exiting
+func (l *Logger) FatalErr(err error) {
+	l.logMessage(FATAL, err.Error(), err)
+}
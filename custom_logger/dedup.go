@@ -0,0 +1,78 @@
+package custom_logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithDeduplication collapses repeats of an identical line, same level,
+// message and fields, logged within window of its first occurrence. The
+// first line is written at once; when the window closes, one summary line
+// "<message> (repeated N times)" stands in for the repeats that were
+// suppressed. FATAL lines are never suppressed.
+func WithDeduplication(window time.Duration) Option {
+	return func(l *Logger) {
+		l.dedup = &deduplicator{window: window, seen: make(map[string]*repeat)}
+	}
+}
+
+// deduplicator tracks lines seen within the current window. Loggers derived
+// with WithFields share their parent's deduplicator.
+type deduplicator struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]*repeat
+}
+
+// repeat counts the suppressed repeats of one line
+type repeat struct {
+	entry Entry
+	count int
+}
+
+// admit reports whether entry should be written. The first occurrence of a
+// line opens a window, after which a summary of any repeats is written
+// through l.
+func (d *deduplicator) admit(l *Logger, entry Entry) bool {
+	if entry.Level == FATAL {
+		return true
+	}
+
+	key := dedupKey(entry)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if r, ok := d.seen[key]; ok {
+		r.count++
+		r.entry.Time = entry.Time
+		return false
+	}
+
+	d.seen[key] = &repeat{entry: entry}
+	time.AfterFunc(d.window, func() {
+		d.mu.Lock()
+		r := d.seen[key]
+		delete(d.seen, key)
+		d.mu.Unlock()
+
+		if r.count > 0 {
+			summary := r.entry
+			summary.Message = fmt.Sprintf("%s (repeated %d times)", r.entry.Message, r.count)
+			l.output(summary)
+		}
+	})
+	return true
+}
+
+// dedupKey identifies a line by its level, message and fields
+func dedupKey(entry Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\x00%s", entry.Level, entry.Message)
+	for _, key := range sortedKeys(entry.Fields) {
+		fmt.Fprintf(&b, "\x00%s=%v", key, entry.Fields[key])
+	}
+	return b.String()
+}
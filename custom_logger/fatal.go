@@ -0,0 +1,51 @@
+package custom_logger
+
+import (
+	"os"
+	"sync"
+)
+
+// FatalAction runs after a FATAL entry has been written, with the error
+// passed to FatalErr or the message of Fatal
+type FatalAction func(err error)
+
+// Built-in fatal actions
+var (
+	// ExitOnFatal exits the program with status 1; it is the default
+	ExitOnFatal FatalAction = func(error) { os.Exit(1) }
+	// PanicOnFatal panics with the error, so deferred calls still run
+	PanicOnFatal FatalAction = func(err error) { panic(err) }
+	// ContinueOnFatal only logs, and Fatal returns to its caller
+	ContinueOnFatal FatalAction = func(error) {}
+)
+
+// WithFatalAction sets what Fatal and FatalErr do after logging
+func WithFatalAction(action FatalAction) Option {
+	return func(l *Logger) {
+		l.SetFatalAction(action)
+	}
+}
+
+// SetFatalAction changes the fatal action of the logger and every logger
+// derived from it. It can be set after the scheduler using the logger has
+// been created, for actions that need the scheduler.
+func (l *Logger) SetFatalAction(action FatalAction) {
+	if action == nil {
+		action = ExitOnFatal
+	}
+	l.fatal.mu.Lock()
+	defer l.fatal.mu.Unlock()
+	l.fatal.action = action
+}
+
+// fatalHandler is the fatal action shared by a root logger and its derived loggers
+type fatalHandler struct {
+	mu     sync.Mutex
+	action FatalAction
+}
+
+func (h *fatalHandler) get() FatalAction {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.action
+}
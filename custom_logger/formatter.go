@@ -0,0 +1,116 @@
+package custom_logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a single log record handed to a Formatter
+type Entry struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	Fields  map[string]interface{}
+
+	// Caller is the file:line that logged the entry and Function the
+	// calling function, when the logger records them
+	Caller   string
+	Function string
+}
+
+// Formatter renders an Entry as one line of output, without the newline
+type Formatter interface {
+	Format(entry Entry) ([]byte, error)
+}
+
+// TextFormatter renders "[2006-01-02 15:04:05] [LEVEL] message", followed
+// by any fields as sorted key=value pairs, quoting values where needed.
+// The call site, when recorded, goes in brackets before the message.
+type TextFormatter struct{}
+
+// Format implements Formatter
+func (TextFormatter) Format(entry Entry) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] [%s] ", entry.Time.Format("2006-01-02 15:04:05"), entry.Level)
+	if site := callSite(entry); site != "" {
+		fmt.Fprintf(&b, "[%s] ", site)
+	}
+	b.WriteString(entry.Message)
+	for _, key := range sortedKeys(entry.Fields) {
+		fmt.Fprintf(&b, " %s=%s", key, fieldValue(entry.Fields[key]))
+	}
+	return []byte(b.String()), nil
+}
+
+// JSONFormatter renders one JSON object per line with the keys ts, level,
+// msg and, when present, fields
+type JSONFormatter struct{}
+
+// jsonEntry is the wire representation used by JSONFormatter
+type jsonEntry struct {
+	Time     string                 `json:"ts"`
+	Level    string                 `json:"level"`
+	Message  string                 `json:"msg"`
+	Caller   string                 `json:"caller,omitempty"`
+	Function string                 `json:"func,omitempty"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Format implements Formatter
+func (JSONFormatter) Format(entry Entry) ([]byte, error) {
+	fields := entry.Fields
+	copied := false
+	for key, value := range entry.Fields {
+		// Errors marshal as {} unless turned into their message
+		if err, ok := value.(error); ok {
+			if !copied {
+				fields, copied = copyFields(entry.Fields), true
+			}
+			fields[key] = err.Error()
+		}
+	}
+	return json.Marshal(jsonEntry{
+		Time:     entry.Time.Format(time.RFC3339Nano),
+		Level:    entry.Level.String(),
+		Message:  entry.Message,
+		Caller:   entry.Caller,
+		Function: entry.Function,
+		Fields:   fields,
+	})
+}
+
+// callSite joins the entry's caller and function, either of which may be empty
+func callSite(entry Entry) string {
+	return strings.TrimSpace(entry.Caller + " " + entry.Function)
+}
+
+// fieldValue renders a field value for key=value output, quoting it when
+// it is empty or contains spaces, quotes or '=' so lines stay parseable
+func fieldValue(value interface{}) string {
+	s := fmt.Sprint(value)
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		out[key] = value
+	}
+	return out
+}
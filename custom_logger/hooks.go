@@ -0,0 +1,52 @@
+package custom_logger
+
+import "sync"
+
+// Hook receives every ERROR and FATAL entry a logger writes, for alerting
+// or error counting. Hooks run synchronously before the entry is written,
+// so they should return quickly.
+type Hook interface {
+	Fire(entry Entry)
+}
+
+// HookFunc adapts a plain function to the Hook interface
+type HookFunc func(entry Entry)
+
+// Fire calls f(entry)
+func (f HookFunc) Fire(entry Entry) {
+	f(entry)
+}
+
+// WithHook registers a hook when the logger is created
+func WithHook(hook Hook) Option {
+	return func(l *Logger) {
+		l.AddHook(hook)
+	}
+}
+
+// AddHook registers a hook on the logger and on every logger derived from
+// it with WithFields or Named, including ones created earlier
+func (l *Logger) AddHook(hook Hook) {
+	l.hooks.mu.Lock()
+	defer l.hooks.mu.Unlock()
+	l.hooks.list = append(l.hooks.list, hook)
+}
+
+// hookSet is the hook list shared by a root logger and its derived loggers
+type hookSet struct {
+	mu   sync.RWMutex
+	list []Hook
+}
+
+// fire hands entry to every hook if it is an ERROR or FATAL entry
+func (h *hookSet) fire(entry Entry) {
+	if entry.Level < ERROR {
+		return
+	}
+	h.mu.RLock()
+	hooks := h.list
+	h.mu.RUnlock()
+	for _, hook := range hooks {
+		hook.Fire(entry)
+	}
+}
@@ -0,0 +1,108 @@
+package custom_logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// journalSocket is where systemd-journald accepts native protocol messages
+const journalSocket = "/run/systemd/journal/socket"
+
+// JournalWriter sends entries to systemd-journald over its native protocol.
+// Fields become journal fields with upper-cased names, so they can be
+// filtered with journalctl, e.g. journalctl JOB=backup.
+type JournalWriter struct {
+	identifier string
+	conn       *net.UnixConn
+	addr       *net.UnixAddr
+}
+
+// NewJournalWriter opens the journald socket. An empty identifier uses the
+// program name as SYSLOG_IDENTIFIER.
+func NewJournalWriter(identifier string) (*JournalWriter, error) {
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+	if _, err := os.Stat(journalSocket); err != nil {
+		return nil, fmt.Errorf("journald not available: %w", err)
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("open journal socket: %w", err)
+	}
+	addr := &net.UnixAddr{Name: journalSocket, Net: "unixgram"}
+	return &JournalWriter{identifier: identifier, conn: conn, addr: addr}, nil
+}
+
+// WriteEntry sends entry with the priority matching its level
+func (w *JournalWriter) WriteEntry(entry Entry) error {
+	var b bytes.Buffer
+	journalField(&b, "MESSAGE", entry.Message)
+	journalField(&b, "PRIORITY", fmt.Sprint(syslogSeverity(entry.Level)))
+	journalField(&b, "SYSLOG_IDENTIFIER", w.identifier)
+	for _, key := range sortedKeys(entry.Fields) {
+		if name := journalName(key); name != "" {
+			journalField(&b, name, fmt.Sprint(entry.Fields[key]))
+		}
+	}
+
+	if _, err := w.conn.WriteToUnix(b.Bytes(), w.addr); err != nil {
+		return fmt.Errorf("write to journal: %w", err)
+	}
+	return nil
+}
+
+// Write sends p as an INFO message, for use as a plain io.Writer
+func (w *JournalWriter) Write(p []byte) (int, error) {
+	entry := Entry{Time: time.Now(), Level: INFO, Message: strings.TrimRight(string(p), "\n")}
+	if err := w.WriteEntry(entry); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the socket
+func (w *JournalWriter) Close() error {
+	return w.conn.Close()
+}
+
+// journalField appends one field. Values containing newlines use the
+// binary form: name, newline, little-endian 64-bit length, value.
+func journalField(b *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(b, "%s=%s\n", name, value)
+		return
+	}
+	b.WriteString(name)
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// journalName turns key into a journal field name: upper-case letters,
+// digits and underscores, not starting with an underscore or digit, which
+// journald reserves or rejects
+func journalName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	name = strings.TrimLeft(name, "_0123456789")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
@@ -0,0 +1,45 @@
+package custom_logger
+
+import (
+	"io"
+	"os"
+)
+
+// Output is one destination of a multi-output logger
+type Output struct {
+	Writer io.Writer
+	// Level is the minimum level written to this output
+	Level LogLevel
+	// Formatter renders entries for this output; nil uses the logger's
+	// formatter
+	Formatter Formatter
+}
+
+// EntryWriter is a writer that takes whole entries rather than formatted
+// lines, for backends such as syslog and journald that carry the level and
+// fields themselves. Outputs whose writer is an EntryWriter skip their
+// formatter.
+type EntryWriter interface {
+	io.Writer
+	WriteEntry(entry Entry) error
+}
+
+// NewMultiLogger creates a Logger that tees every entry to several outputs,
+// each with its own minimum level and formatter, e.g. DEBUG as JSON to a
+// rotating file and ERROR as text to stderr. An output without a writer
+// writes to stdout.
+func NewMultiLogger(outputs []Output, opts ...Option) *Logger {
+	// The logger admits the lowest level any output wants
+	level := FATAL
+	outs := make([]Output, len(outputs))
+	for i, out := range outputs {
+		if out.Writer == nil {
+			out.Writer = os.Stdout
+		}
+		if out.Level < level {
+			level = out.Level
+		}
+		outs[i] = out
+	}
+	return newLogger(level, outs, opts)
+}
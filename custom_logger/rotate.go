@@ -0,0 +1,273 @@
+package custom_logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp inserted into rotated file names
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFile is an io.WriteCloser writing to a file that is rotated once
+// it would grow past a maximum size. Rotated files are renamed to
+// name-<timestamp>.ext next to the original, optionally gzipped, and pruned
+// by count and age. Use it as the output of NewLogger.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// millMu serializes compression and pruning of rotated files
+	millMu sync.Mutex
+	mills  sync.WaitGroup
+}
+
+// RotateOption represents a configuration option for RotatingFile
+type RotateOption func(*RotatingFile)
+
+// WithMaxSize rotates the file before it grows past bytes; the default is
+// 100 MiB
+func WithMaxSize(bytes int64) RotateOption {
+	return func(f *RotatingFile) {
+		f.maxSize = bytes
+	}
+}
+
+// WithMaxAge deletes rotated files older than age
+func WithMaxAge(age time.Duration) RotateOption {
+	return func(f *RotatingFile) {
+		f.maxAge = age
+	}
+}
+
+// WithMaxBackups keeps at most n rotated files, deleting the oldest
+func WithMaxBackups(n int) RotateOption {
+	return func(f *RotatingFile) {
+		f.maxBackups = n
+	}
+}
+
+// WithCompression gzips rotated files
+func WithCompression() RotateOption {
+	return func(f *RotatingFile) {
+		f.compress = true
+	}
+}
+
+// NewRotatingFile opens path for appending, creating it and its directory
+// if needed
+func NewRotatingFile(path string, opts ...RotateOption) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, maxSize: 100 << 20}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p, rotating first if p would take the file past its
+// maximum size. A single write larger than the maximum is written whole.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, fmt.Errorf("rotating file %s is closed", f.path)
+	}
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate closes the current file, renames it to a backup and starts a new one
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return fmt.Errorf("rotating file %s is closed", f.path)
+	}
+	return f.rotate()
+}
+
+// Close closes the file and waits for pending compression and pruning
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.mills.Wait()
+	return err
+}
+
+// open opens the log file and records its current size; callers hold mu
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file aside and opens a fresh one; callers hold mu
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+	f.file = nil
+
+	backup := f.backupName(time.Now())
+	if err := os.Rename(f.path, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.mills.Add(1)
+	go func() {
+		defer f.mills.Done()
+		f.mill(backup)
+	}()
+	return nil
+}
+
+// backupName returns the name of a file rotated at t
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.nameParts()
+	return filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+}
+
+// nameParts splits the path into its directory, the backup name prefix and
+// the extension
+func (f *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(f.path)
+	base := filepath.Base(f.path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// mill compresses the new backup if configured, then prunes old backups.
+// Failures are reported on stderr, since the log itself may be the problem.
+func (f *RotatingFile) mill(backup string) {
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	if f.compress {
+		if err := compressFile(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "compress %s: %v\n", backup, err)
+		}
+	}
+	if err := f.prune(); err != nil {
+		fmt.Fprintf(os.Stderr, "prune backups of %s: %v\n", f.path, err)
+	}
+}
+
+// prune deletes backups beyond the maximum count or age
+func (f *RotatingFile) prune() error {
+	if f.maxBackups <= 0 && f.maxAge <= 0 {
+		return nil
+	}
+
+	dir, prefix, ext := f.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		name string
+		time time.Time
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		stamp := strings.TrimPrefix(name, prefix)
+		if stamp == name || entry.IsDir() {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		t, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{name, t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+
+	cutoff := time.Now().Add(-f.maxAge)
+	for i, b := range backups {
+		tooMany := f.maxBackups > 0 && i >= f.maxBackups
+		tooOld := f.maxAge > 0 && b.time.Before(cutoff)
+		if tooMany || tooOld {
+			if err := os.Remove(filepath.Join(dir, b.name)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compressFile gzips path into path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
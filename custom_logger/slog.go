@@ -0,0 +1,167 @@
+package custom_logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Handler is a slog.Handler that writes records through a Logger, so
+// slog-based code shares the logger's level, formatter and output. Attrs
+// become entry fields, with group names joined to keys by dots.
+type Handler struct {
+	logger *Logger
+	fields map[string]interface{}
+	prefix string
+}
+
+// NewHandler creates a slog.Handler backed by logger
+func NewHandler(logger *Logger) *Handler {
+	return &Handler{logger: logger}
+}
+
+// Enabled reports whether the logger's minimum level admits level
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.Enabled(levelFromSlog(level))
+}
+
+// Handle writes the record as a single entry
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	fields := copyFields(h.fields)
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(fields, h.prefix, attr)
+		return true
+	})
+
+	t := record.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	entry := Entry{
+		Time:    t,
+		Level:   levelFromSlog(record.Level),
+		Message: record.Message,
+		Fields:  fields,
+	}
+	if h.logger.caller != callerOff && record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		h.logger.setCaller(&entry, frame)
+	}
+	return h.logger.LogEntry(entry)
+}
+
+// WithAttrs returns a handler that adds attrs to every record
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	child := *h
+	child.fields = copyFields(h.fields)
+	for _, attr := range attrs {
+		addAttr(child.fields, h.prefix, attr)
+	}
+	return &child
+}
+
+// WithGroup returns a handler that nests later attrs under name
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := *h
+	child.prefix = h.prefix + name + "."
+	return &child
+}
+
+// addAttr flattens attr into fields, joining group names with dots
+func addAttr(fields map[string]interface{}, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		// Groups without a key are inlined, as slog specifies
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			addAttr(fields, prefix, member)
+		}
+		return
+	}
+	fields[prefix+attr.Key] = attr.Value.Any()
+}
+
+// levelFromSlog maps a slog level onto the nearest LogLevel. Levels above
+// error stay ERROR, since FATAL would exit the program.
+func levelFromSlog(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARNING
+	default:
+		return ERROR
+	}
+}
+
+// SlogLogger wraps a *slog.Logger in the printf-style methods of Logger,
+// so it can be passed to better_cron.WithLogger
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// FromSlog wraps logger; a nil logger uses slog.Default()
+func FromSlog(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{logger: logger}
+}
+
+// Debug logs a debug message
+func (l *SlogLogger) Debug(format string, args ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(format, args...))
+}
+
+// Info logs an info message
+func (l *SlogLogger) Info(format string, args ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, args...))
+}
+
+// Warning logs a warning message
+func (l *SlogLogger) Warning(format string, args ...interface{}) {
+	l.logger.Warn(fmt.Sprintf(format, args...))
+}
+
+// Error logs an error message
+func (l *SlogLogger) Error(format string, args ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, args...))
+}
+
+// Fatal logs an error message and exits the program
+func (l *SlogLogger) Fatal(format string, args ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+// InfoFields logs an info message with fields as attrs
+func (l *SlogLogger) InfoFields(fields map[string]interface{}, format string, args ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, args...), fieldArgs(fields)...)
+}
+
+// ErrorFields logs an error message with fields as attrs
+func (l *SlogLogger) ErrorFields(fields map[string]interface{}, format string, args ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, args...), fieldArgs(fields)...)
+}
+
+// fieldArgs turns fields into sorted key-value arguments for slog
+func fieldArgs(fields map[string]interface{}) []interface{} {
+	args := make([]interface{}, 0, 2*len(fields))
+	for _, key := range sortedKeys(fields) {
+		args = append(args, key, fields[key])
+	}
+	return args
+}
@@ -0,0 +1,204 @@
+package custom_logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Syslog facilities
+const (
+	FacilityUser   = 1
+	FacilityDaemon = 3
+	FacilityLocal0 = 16
+)
+
+// syslogSockets are the local syslog sockets tried when no address is given
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// syslogSDID names the structured data element carrying entry fields; 32473
+// is the enterprise number reserved for documentation by RFC 5612
+const syslogSDID = "fields@32473"
+
+// SyslogWriter sends entries to a syslog server as RFC 5424 messages. The
+// message is the entry's message and fields travel as structured data.
+type SyslogWriter struct {
+	network  string
+	addr     string
+	appName  string
+	hostname string
+	facility int
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// SyslogOption represents a configuration option for SyslogWriter
+type SyslogOption func(*SyslogWriter)
+
+// WithFacility sets the syslog facility; the default is FacilityUser
+func WithFacility(facility int) SyslogOption {
+	return func(w *SyslogWriter) {
+		w.facility = facility
+	}
+}
+
+// NewSyslogWriter connects to a syslog server. network is "udp", "tcp" or
+// "unixgram"; an empty network and address use the local syslog socket.
+// An empty appName uses the program name.
+func NewSyslogWriter(network, addr, appName string, opts ...SyslogOption) (*SyslogWriter, error) {
+	if appName == "" {
+		appName = filepath.Base(os.Args[0])
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	w := &SyslogWriter{
+		network:  network,
+		addr:     addr,
+		appName:  appName,
+		hostname: hostname,
+		facility: FacilityUser,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// connect dials the server; callers other than the constructor hold mu
+func (w *SyslogWriter) connect() error {
+	if w.network != "" || w.addr != "" {
+		conn, err := net.Dial(w.network, w.addr)
+		if err != nil {
+			return fmt.Errorf("connect to syslog: %w", err)
+		}
+		w.conn = conn
+		return nil
+	}
+
+	for _, path := range syslogSockets {
+		if conn, err := net.Dial("unixgram", path); err == nil {
+			w.conn = conn
+			return nil
+		}
+	}
+	return fmt.Errorf("connect to syslog: no local syslog socket found")
+}
+
+// WriteEntry sends entry with the priority matching its level
+func (w *SyslogWriter) WriteEntry(entry Entry) error {
+	msg := w.format(entry)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Reconnect once if the server went away
+	var err error
+	for i := 0; i < 2; i++ {
+		if w.conn == nil {
+			if err = w.connect(); err != nil {
+				continue
+			}
+		}
+		if _, err = w.conn.Write(w.frame(msg)); err == nil {
+			return nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	return err
+}
+
+// Write sends p as an INFO message, for use as a plain io.Writer
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	entry := Entry{Time: time.Now(), Level: INFO, Message: strings.TrimRight(string(p), "\n")}
+	if err := w.WriteEntry(entry); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the connection
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// format renders entry as an RFC 5424 message
+func (w *SyslogWriter) format(entry Entry) string {
+	pri := w.facility*8 + syslogSeverity(entry.Level)
+	timestamp := entry.Time.Format("2006-01-02T15:04:05.000000Z07:00")
+	return fmt.Sprintf("<%d>1 %s %s %s %d - %s %s",
+		pri, timestamp, w.hostname, w.appName, os.Getpid(), structuredData(entry.Fields), entry.Message)
+}
+
+// frame prefixes stream connections with the message length (RFC 6587
+// octet counting); datagrams are sent as they are
+func (w *SyslogWriter) frame(msg string) []byte {
+	if _, ok := w.conn.(*net.TCPConn); ok {
+		return []byte(fmt.Sprintf("%d %s", len(msg), msg))
+	}
+	return []byte(msg)
+}
+
+// syslogSeverity maps a LogLevel onto a syslog severity
+func syslogSeverity(level LogLevel) int {
+	switch level {
+	case DEBUG:
+		return 7
+	case INFO:
+		return 6
+	case WARNING:
+		return 4
+	case ERROR:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// structuredData renders fields as one SD element, or "-" if there are none
+func structuredData(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return "-"
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	var b strings.Builder
+	b.WriteString("[" + syslogSDID)
+	for _, key := range sortedKeys(fields) {
+		fmt.Fprintf(&b, ` %s="%s"`, sdName(key), escaper.Replace(fmt.Sprint(fields[key])))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// sdName makes key a valid SD-NAME: printable ASCII without '=', ' ', ']'
+// or '"', at most 32 characters
+func sdName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return name
+}
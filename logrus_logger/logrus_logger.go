@@ -0,0 +1,118 @@
+package logrus_logger
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"cron_test/custom_logger"
+	"github.com/sirupsen/logrus"
+)
+
+// Logger wraps a logrus logger or entry in the printf-style methods of
+// custom_logger.Logger, so it can be passed to better_cron.WithLogger
+type Logger struct {
+	logger logrus.FieldLogger
+}
+
+// New wraps logger, which may be a *logrus.Logger or a *logrus.Entry
+// carrying fields
+func New(logger logrus.FieldLogger) *Logger {
+	return &Logger{logger: logger}
+}
+
+// Debug logs a debug message
+func (l *Logger) Debug(format string, args ...interface{}) {
+	l.logger.Debugf(format, args...)
+}
+
+// Info logs an info message
+func (l *Logger) Info(format string, args ...interface{}) {
+	l.logger.Infof(format, args...)
+}
+
+// Warning logs a warning message
+func (l *Logger) Warning(format string, args ...interface{}) {
+	l.logger.Warnf(format, args...)
+}
+
+// Error logs an error message
+func (l *Logger) Error(format string, args ...interface{}) {
+	l.logger.Errorf(format, args...)
+}
+
+// Fatal logs a fatal message and exits the program
+func (l *Logger) Fatal(format string, args ...interface{}) {
+	l.logger.Fatalf(format, args...)
+}
+
+// InfoFields logs an info message with fields as logrus fields
+func (l *Logger) InfoFields(fields map[string]interface{}, format string, args ...interface{}) {
+	l.logger.WithFields(fields).Infof(format, args...)
+}
+
+// ErrorFields logs an error message with fields as logrus fields
+func (l *Logger) ErrorFields(fields map[string]interface{}, format string, args ...interface{}) {
+	l.logger.WithFields(fields).Errorf(format, args...)
+}
+
+// Hook is a logrus.Hook that copies every entry to a custom_logger.Logger
+type Hook struct {
+	logger *custom_logger.Logger
+}
+
+// NewHook creates a hook writing to logger
+func NewHook(logger *custom_logger.Logger) *Hook {
+	return &Hook{logger: logger}
+}
+
+// Levels reports that the hook receives every level; Fire filters them
+// against the logger's minimum level
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire writes the entry through the logger
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	level := levelFromLogrus(entry.Level)
+	if !h.logger.Enabled(level) {
+		return nil
+	}
+	out := custom_logger.Entry{
+		Time:    entry.Time,
+		Level:   level,
+		Message: entry.Message,
+		Fields:  entry.Data,
+	}
+	// Set when the logrus logger has ReportCaller enabled
+	if entry.Caller != nil {
+		out.Caller = fmt.Sprintf("%s:%d", filepath.Base(entry.Caller.File), entry.Caller.Line)
+		out.Function = entry.Caller.Function
+	}
+	return h.logger.LogEntry(out)
+}
+
+// ToLogrus returns a *logrus.Logger whose output goes only to logger, for
+// code that expects logrus
+func ToLogrus(logger *custom_logger.Logger) *logrus.Logger {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	l.SetLevel(logrus.TraceLevel)
+	l.AddHook(NewHook(logger))
+	return l
+}
+
+// levelFromLogrus maps a logrus level onto the nearest LogLevel. Panic and
+// fatal levels map to ERROR; logrus itself panics or exits after them.
+func levelFromLogrus(level logrus.Level) custom_logger.LogLevel {
+	switch level {
+	case logrus.TraceLevel, logrus.DebugLevel:
+		return custom_logger.DEBUG
+	case logrus.InfoLevel:
+		return custom_logger.INFO
+	case logrus.WarnLevel:
+		return custom_logger.WARNING
+	default:
+		return custom_logger.ERROR
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"cron_test/better_cron"
 	"cron_test/custom_logger"
 	"fmt"
@@ -21,6 +22,9 @@ func main() {
 		better_cron.WithLogger(logger),
 	)
 
+	// Fatal errors shut the scheduler down gracefully before exiting
+	logger.SetFatalAction(ec.ShutdownOnFatal(better_cron.ShutdownDrain, os.Exit))
+
 	// Add jobs
 	ec.AddJob("*/5 * * * * *", cron.FuncJob(func() {
 		fmt.Println("Running job...")
@@ -40,8 +44,8 @@ func main() {
 
 	log.Println("Shutdown signal received, initiating graceful shutdown...")
 
-	// Initiate graceful shutdown
-	if err := ec.Shutdown(); err != nil {
+	// Initiate graceful shutdown, letting running jobs finish
+	if err := ec.Shutdown(context.Background(), better_cron.ShutdownDrain); err != nil {
 		log.Printf("Shutdown error: %v", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,128 @@
+// Package otel_metrics reports better_cron scheduler metrics through the
+// OpenTelemetry metrics API, for stacks that collect over OTLP.
+package otel_metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"cron_test/better_cron"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// scope is the instrumentation scope name of the meter
+const scope = "cron_test/better_cron"
+
+// Emitter records run outcomes as OpenTelemetry instruments. As an
+// EventSink it records job.duration (a histogram in seconds), job.success,
+// job.failure and job.skipped (counters), attributed with the job name. It
+// is also a MetricsRecorder, so passing it to better_cron.WithMetrics
+// records the queue and pool gauges too.
+type Emitter struct {
+	meter    metric.Meter
+	duration metric.Float64Histogram
+	success  metric.Int64Counter
+	failure  metric.Int64Counter
+	skipped  metric.Int64Counter
+
+	mu     sync.Mutex
+	gauges map[string]metric.Float64Gauge
+}
+
+// Option represents configuration options for Emitter
+type Option func(*config)
+
+type config struct {
+	provider metric.MeterProvider
+}
+
+// WithMeterProvider sets the provider the meter is taken from; the default
+// is the global provider from otel.GetMeterProvider
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(cfg *config) {
+		cfg.provider = provider
+	}
+}
+
+// New creates an emitter and its instruments
+func New(opts ...Option) (*Emitter, error) {
+	cfg := config{provider: otel.GetMeterProvider()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	e := &Emitter{meter: cfg.provider.Meter(scope), gauges: make(map[string]metric.Float64Gauge)}
+	var err error
+	if e.duration, err = e.meter.Float64Histogram("job.duration",
+		metric.WithUnit("s"), metric.WithDescription("Duration of finished job runs")); err != nil {
+		return nil, fmt.Errorf("create job.duration: %w", err)
+	}
+	if e.success, err = e.meter.Int64Counter("job.success",
+		metric.WithUnit("{run}"), metric.WithDescription("Job runs that completed")); err != nil {
+		return nil, fmt.Errorf("create job.success: %w", err)
+	}
+	if e.failure, err = e.meter.Int64Counter("job.failure",
+		metric.WithUnit("{run}"), metric.WithDescription("Job runs that failed")); err != nil {
+		return nil, fmt.Errorf("create job.failure: %w", err)
+	}
+	if e.skipped, err = e.meter.Int64Counter("job.skipped",
+		metric.WithUnit("{fire}"), metric.WithDescription("Job fires that were not run")); err != nil {
+		return nil, fmt.Errorf("create job.skipped: %w", err)
+	}
+	return e, nil
+}
+
+// HandleEvent records finished runs and skipped fires
+func (e *Emitter) HandleEvent(event better_cron.JobEvent) {
+	ctx := context.Background()
+	job := attribute.String("job", event.Job)
+
+	switch event.Type {
+	case better_cron.EventJobCompleted, better_cron.EventJobFailed:
+		m := event.Metadata
+		if !m.StartTime.IsZero() && !m.EndTime.IsZero() {
+			e.duration.Record(ctx, m.EndTime.Sub(m.StartTime).Seconds(),
+				metric.WithAttributes(job, attribute.String("status", m.Status.String())))
+		}
+		if event.Type == better_cron.EventJobCompleted {
+			e.success.Add(ctx, 1, metric.WithAttributes(job))
+		} else {
+			e.failure.Add(ctx, 1, metric.WithAttributes(job))
+		}
+	case better_cron.EventJobSkipped:
+		e.skipped.Add(ctx, 1, metric.WithAttributes(job, attribute.String("reason", event.Reason)))
+	}
+}
+
+// Gauge records a gauge, creating its instrument on first use
+func (e *Emitter) Gauge(name string, value float64, tags map[string]string) {
+	gauge, err := e.gauge(name)
+	if err != nil {
+		otel.Handle(err)
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(tags))
+	for key, value := range tags {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	gauge.Record(context.Background(), value, metric.WithAttributes(attrs...))
+}
+
+func (e *Emitter) gauge(name string) (metric.Float64Gauge, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if gauge, ok := e.gauges[name]; ok {
+		return gauge, nil
+	}
+	gauge, err := e.meter.Float64Gauge(name)
+	if err != nil {
+		return nil, fmt.Errorf("create %s: %w", name, err)
+	}
+	e.gauges[name] = gauge
+	return gauge, nil
+}
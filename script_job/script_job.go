@@ -0,0 +1,268 @@
+package script_job
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cron_test/better_cron"
+	"github.com/robfig/cron/v3"
+	"go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// Result is the run result recorded for a script job
+type Result struct {
+	// Output collects everything the script printed
+	Output string
+	// Value is the return value of the script's run() function, converted
+	// to plain Go values
+	Value interface{}
+}
+
+// Job runs a Starlark script loaded from a file. The file is re-read
+// whenever it changes, so the job body can be edited without recompiling
+// or restarting the scheduler.
+//
+// The script is executed top to bottom on every run. If it defines a run()
+// function, it is called and its return value becomes Result.Value. Scripts
+// can use print(), the json module and any values given in Globals.
+type Job struct {
+	Path string
+	// MaxSteps aborts runaway scripts after this many execution steps; 0
+	// means unlimited
+	MaxSteps uint64
+	// Globals are predeclared for the script; supported value types are
+	// strings, bools, ints, floats and nested slices and maps of them
+	Globals map[string]interface{}
+
+	mu      sync.Mutex
+	src     []byte
+	modTime time.Time
+}
+
+// Load creates a script job for path and checks that the script parses
+func Load(path string) (*Job, error) {
+	j := &Job{Path: path}
+	src, err := j.source()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := syntax.Parse(path, src, 0); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// LoadDir loads every *.star file in dir, keyed by file name without the
+// extension
+func LoadDir(dir string) (map[string]*Job, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.star"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	jobs := make(map[string]*Job, len(paths))
+	for _, path := range paths {
+		j, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		jobs[strings.TrimSuffix(filepath.Base(path), ".star")] = j
+	}
+	return jobs, nil
+}
+
+// source returns the script contents, re-reading the file if it changed
+func (j *Job) source() ([]byte, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	info, err := os.Stat(j.Path)
+	if err != nil {
+		return nil, err
+	}
+	if j.src != nil && info.ModTime().Equal(j.modTime) {
+		return j.src, nil
+	}
+
+	src, err := os.ReadFile(j.Path)
+	if err != nil {
+		return nil, err
+	}
+	j.src, j.modTime = src, info.ModTime()
+	return src, nil
+}
+
+// Run executes the script with a background context
+func (j *Job) Run() { _, _ = j.RunResult(context.Background()) }
+
+// RunResult executes the script, cancelling it when ctx is done
+func (j *Job) RunResult(ctx context.Context) (interface{}, error) {
+	src, err := j.source()
+	if err != nil {
+		return nil, err
+	}
+
+	predeclared := starlark.StringDict{"json": json.Module}
+	for name, value := range j.Globals {
+		v, err := toStarlark(value)
+		if err != nil {
+			return nil, fmt.Errorf("global %s: %w", name, err)
+		}
+		predeclared[name] = v
+	}
+
+	var output strings.Builder
+	thread := &starlark.Thread{
+		Name: j.Path,
+		Print: func(_ *starlark.Thread, msg string) {
+			output.WriteString(msg)
+			output.WriteByte('\n')
+		},
+	}
+	if j.MaxSteps > 0 {
+		thread.SetMaxExecutionSteps(j.MaxSteps)
+	}
+
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	defer stop()
+
+	result := &Result{}
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, j.Path, src, predeclared)
+	if err == nil {
+		if fn, ok := globals["run"].(starlark.Callable); ok {
+			var value starlark.Value
+			value, err = starlark.Call(thread, fn, nil, nil)
+			if err == nil {
+				result.Value = fromStarlark(value)
+			}
+		}
+	}
+	result.Output = output.String()
+
+	if err != nil {
+		return result, fmt.Errorf("script %s: %w", j.Path, err)
+	}
+	return result, nil
+}
+
+// toStarlark converts a plain Go value into a Starlark value
+func toStarlark(v interface{}) (starlark.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case string:
+		return starlark.String(v), nil
+	case bool:
+		return starlark.Bool(v), nil
+	case int:
+		return starlark.MakeInt(v), nil
+	case int64:
+		return starlark.MakeInt64(v), nil
+	case float64:
+		return starlark.Float(v), nil
+	case []interface{}:
+		elems := make([]starlark.Value, len(v))
+		for i, e := range v {
+			sv, err := toStarlark(e)
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = sv
+		}
+		return starlark.NewList(elems), nil
+	case map[string]interface{}:
+		dict := starlark.NewDict(len(v))
+		for k, e := range v {
+			sv, err := toStarlark(e)
+			if err != nil {
+				return nil, err
+			}
+			if err := dict.SetKey(starlark.String(k), sv); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	case map[string]string:
+		dict := starlark.NewDict(len(v))
+		for k, e := range v {
+			if err := dict.SetKey(starlark.String(k), starlark.String(e)); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+// fromStarlark converts a Starlark value into plain Go values
+func fromStarlark(v starlark.Value) interface{} {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil
+	case starlark.Bool:
+		return bool(v)
+	case starlark.Int:
+		if i, ok := v.Int64(); ok {
+			return i
+		}
+		return v.String()
+	case starlark.Float:
+		return float64(v)
+	case starlark.String:
+		return string(v)
+	case starlark.Indexable:
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = fromStarlark(v.Index(i))
+		}
+		return out
+	case *starlark.Dict:
+		out := make(map[string]interface{}, v.Len())
+		for _, item := range v.Items() {
+			key := item[0].String()
+			if s, ok := item[0].(starlark.String); ok {
+				key = string(s)
+			}
+			out[key] = fromStarlark(item[1])
+		}
+		return out
+	default:
+		return v.String()
+	}
+}
+
+// Factory builds script jobs from job definition files. Register it with
+//
+//	better_cron.RegisterJobType("script", script_job.Factory)
+//
+// and declare jobs with options.path and optionally options.max_steps.
+func Factory(def better_cron.JobDefinition) (cron.Job, error) {
+	path, _ := def.Options["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("options.path is required")
+	}
+
+	j, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	switch steps := def.Options["max_steps"].(type) {
+	case int:
+		j.MaxSteps = uint64(steps)
+	case int64:
+		j.MaxSteps = uint64(steps)
+	case float64:
+		j.MaxSteps = uint64(steps)
+	}
+	return j, nil
+}
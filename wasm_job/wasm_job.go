@@ -0,0 +1,277 @@
+package wasm_job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cron_test/better_cron"
+	"github.com/robfig/cron/v3"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// Result is the run result recorded for a WASM job
+type Result struct {
+	// Output collects the messages the module passed to env.log
+	Output string
+	// Value is the payload the module passed to env.set_result
+	Value []byte
+	// ExitCode is the value returned by the entry function, or the WASI exit code
+	ExitCode uint32
+}
+
+// Config holds the sandbox limits of a WASM job
+type Config struct {
+	// Entry is the exported function to call; it takes no parameters and
+	// returns nothing or an i32 exit code. Defaults to "run".
+	Entry string
+	// MemoryLimitPages caps linear memory in 64 KiB pages; defaults to 256 (16 MiB)
+	MemoryLimitPages uint32
+	// MaxDuration aborts the module after this much time, in addition to the
+	// run context; 0 means only the run context applies
+	MaxDuration time.Duration
+	// Fuel caps the CPU a run may spend by metering it in function calls:
+	// every call into a module function takes one unit and the module is
+	// aborted once Fuel is used up. Loops that make no calls are bounded by
+	// MaxDuration only. 0 means unmetered.
+	Fuel uint64
+	// Input is made available to the module through env.input_len and env.input_read
+	Input []byte
+	// WASI exposes a WASI preview1 environment without filesystem, network,
+	// environment variables or arguments, for modules built by toolchains
+	// that require it
+	WASI bool
+}
+
+// Job runs an exported function of a WASM module inside a sandbox with
+// memory, time and fuel limits. The module can only import the narrow host API
+// below from the "env" module:
+//
+//	log(ptr, len i32)         append a message to the run output
+//	input_len() i32           size of Config.Input
+//	input_read(ptr i32)       copy Config.Input into memory at ptr
+//	set_result(ptr, len i32)  set the run result payload
+type Job struct {
+	cfg      Config
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// runState is the per-run state reached by the host functions through the
+// call context
+type runState struct {
+	mu     sync.Mutex
+	input  []byte
+	output strings.Builder
+	result []byte
+	// fuel is what is left of Config.Fuel, and abort stops the run once it
+	// is used up
+	fuel      uint64
+	exhausted bool
+	abort     context.CancelFunc
+}
+
+type stateKey struct{}
+
+// Load compiles the module at path into a sandboxed job
+func Load(ctx context.Context, path string, cfg Config) (*Job, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(ctx, code, cfg)
+}
+
+// New compiles a module from its binary into a sandboxed job
+func New(ctx context.Context, code []byte, cfg Config) (*Job, error) {
+	if cfg.Entry == "" {
+		cfg.Entry = "run"
+	}
+	if cfg.MemoryLimitPages == 0 {
+		cfg.MemoryLimitPages = 256
+	}
+
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(cfg.MemoryLimitPages).
+		WithCloseOnContextDone(true))
+
+	if err := instantiateHostAPI(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+	if cfg.WASI {
+		if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+			runtime.Close(ctx)
+			return nil, err
+		}
+	}
+
+	compileCtx := ctx
+	if cfg.Fuel > 0 {
+		compileCtx = experimental.WithFunctionListenerFactory(ctx, fuelMeter{})
+	}
+	compiled, err := runtime.CompileModule(compileCtx, code)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("compile wasm module: %w", err)
+	}
+	if _, ok := compiled.ExportedFunctions()[cfg.Entry]; !ok {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("wasm module does not export %q", cfg.Entry)
+	}
+
+	return &Job{cfg: cfg, runtime: runtime, compiled: compiled}, nil
+}
+
+// Close releases the compiled module and its runtime
+func (j *Job) Close(ctx context.Context) error {
+	return j.runtime.Close(ctx)
+}
+
+// Run executes the module with a background context
+func (j *Job) Run() { _, _ = j.RunResult(context.Background()) }
+
+// RunResult instantiates a fresh copy of the module and calls its entry
+// function. A non-zero exit code is reported as an error.
+func (j *Job) RunResult(ctx context.Context) (interface{}, error) {
+	if j.cfg.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.cfg.MaxDuration)
+		defer cancel()
+	}
+
+	ctx, abort := context.WithCancel(ctx)
+	defer abort()
+	state := &runState{input: j.cfg.Input, fuel: j.cfg.Fuel, abort: abort}
+	ctx = context.WithValue(ctx, stateKey{}, state)
+
+	// Anonymous instances let overlapping runs of the same job coexist
+	mod, err := j.runtime.InstantiateModule(ctx, j.compiled, wazero.NewModuleConfig().
+		WithName("").
+		WithStartFunctions())
+	if err != nil {
+		return nil, fmt.Errorf("instantiate wasm module: %w", err)
+	}
+	defer mod.Close(context.Background())
+
+	results, callErr := mod.ExportedFunction(j.cfg.Entry).Call(ctx)
+
+	state.mu.Lock()
+	result := &Result{Output: state.output.String(), Value: state.result}
+	exhausted := state.exhausted
+	state.mu.Unlock()
+	if len(results) > 0 {
+		result.ExitCode = api.DecodeU32(results[0])
+	}
+
+	var exitErr *sys.ExitError
+	switch {
+	case exhausted:
+		return result, fmt.Errorf("wasm module ran out of fuel after %d calls", j.cfg.Fuel)
+	case errors.As(callErr, &exitErr) && ctx.Err() == nil:
+		result.ExitCode = exitErr.ExitCode()
+	case ctx.Err() != nil:
+		return result, fmt.Errorf("wasm module aborted: %w", ctx.Err())
+	case callErr != nil:
+		return result, fmt.Errorf("wasm module trapped: %w", callErr)
+	}
+
+	if result.ExitCode != 0 {
+		return result, fmt.Errorf("wasm module exited with code %d", result.ExitCode)
+	}
+	return result, nil
+}
+
+// fuelMeter charges a unit of the run's fuel for every function call and
+// aborts the run when none is left
+type fuelMeter struct{}
+
+func (fuelMeter) NewFunctionListener(api.FunctionDefinition) experimental.FunctionListener {
+	return fuelMeter{}
+}
+
+func (fuelMeter) Before(ctx context.Context, _ api.Module, _ api.FunctionDefinition, _ []uint64, _ experimental.StackIterator) {
+	state, ok := ctx.Value(stateKey{}).(*runState)
+	if !ok {
+		return
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.fuel == 0 {
+		state.exhausted = true
+		state.abort()
+		return
+	}
+	state.fuel--
+}
+
+func (fuelMeter) After(context.Context, api.Module, api.FunctionDefinition, []uint64) {}
+
+func (fuelMeter) Abort(context.Context, api.Module, api.FunctionDefinition, error) {}
+
+// instantiateHostAPI registers the "env" functions available to modules
+func instantiateHostAPI(ctx context.Context, runtime wazero.Runtime) error {
+	_, err := runtime.NewHostModuleBuilder("env").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr, size uint32) {
+			state := ctx.Value(stateKey{}).(*runState)
+			if data, ok := m.Memory().Read(ptr, size); ok {
+				state.mu.Lock()
+				state.output.Write(data)
+				state.output.WriteByte('\n')
+				state.mu.Unlock()
+			}
+		}).Export("log").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context) uint32 {
+			return uint32(len(ctx.Value(stateKey{}).(*runState).input))
+		}).Export("input_len").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr uint32) {
+			m.Memory().Write(ptr, ctx.Value(stateKey{}).(*runState).input)
+		}).Export("input_read").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, ptr, size uint32) {
+			state := ctx.Value(stateKey{}).(*runState)
+			if data, ok := m.Memory().Read(ptr, size); ok {
+				state.mu.Lock()
+				state.result = append([]byte(nil), data...)
+				state.mu.Unlock()
+			}
+		}).Export("set_result").
+		Instantiate(ctx)
+	return err
+}
+
+// Factory builds WASM jobs from job definition files. Register it with
+//
+//	better_cron.RegisterJobType("wasm", wasm_job.Factory)
+//
+// and declare jobs with options.path and optionally options.entry and
+// options.fuel.
+func Factory(def better_cron.JobDefinition) (cron.Job, error) {
+	path, _ := def.Options["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("options.path is required")
+	}
+
+	cfg := Config{MaxDuration: time.Duration(def.Timeout)}
+	cfg.Entry, _ = def.Options["entry"].(string)
+	switch fuel := def.Options["fuel"].(type) {
+	case int:
+		cfg.Fuel = uint64(fuel)
+	case int64:
+		cfg.Fuel = uint64(fuel)
+	case float64:
+		cfg.Fuel = uint64(fuel)
+	}
+	return Load(context.Background(), path, cfg)
+}
@@ -0,0 +1,144 @@
+package zap_logger
+
+import (
+	"sort"
+
+	"cron_test/custom_logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger wraps a *zap.Logger in the printf-style methods of
+// custom_logger.Logger, so it can be passed to better_cron.WithLogger
+type Logger struct {
+	sugar *zap.SugaredLogger
+}
+
+// New wraps logger
+func New(logger *zap.Logger) *Logger {
+	// Skip the adapter frame so caller annotations point at the real caller
+	return &Logger{sugar: logger.WithOptions(zap.AddCallerSkip(1)).Sugar()}
+}
+
+// Debug logs a debug message
+func (l *Logger) Debug(format string, args ...interface{}) {
+	l.sugar.Debugf(format, args...)
+}
+
+// Info logs an info message
+func (l *Logger) Info(format string, args ...interface{}) {
+	l.sugar.Infof(format, args...)
+}
+
+// Warning logs a warning message
+func (l *Logger) Warning(format string, args ...interface{}) {
+	l.sugar.Warnf(format, args...)
+}
+
+// Error logs an error message
+func (l *Logger) Error(format string, args ...interface{}) {
+	l.sugar.Errorf(format, args...)
+}
+
+// Fatal logs a fatal message and exits the program
+func (l *Logger) Fatal(format string, args ...interface{}) {
+	l.sugar.Fatalf(format, args...)
+}
+
+// InfoFields logs an info message with fields as zap fields
+func (l *Logger) InfoFields(fields map[string]interface{}, format string, args ...interface{}) {
+	l.sugar.With(keysAndValues(fields)...).Infof(format, args...)
+}
+
+// ErrorFields logs an error message with fields as zap fields
+func (l *Logger) ErrorFields(fields map[string]interface{}, format string, args ...interface{}) {
+	l.sugar.With(keysAndValues(fields)...).Errorf(format, args...)
+}
+
+// keysAndValues flattens fields into sorted key-value pairs for the sugared logger
+func keysAndValues(fields map[string]interface{}) []interface{} {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kv := make([]interface{}, 0, 2*len(keys))
+	for _, key := range keys {
+		kv = append(kv, key, fields[key])
+	}
+	return kv
+}
+
+// NewCore returns a zapcore.Core that writes through logger, so code using
+// zap logs with the logger's level, formatter and output:
+//
+//	zapLogger := zap.New(zap_logger.NewCore(logger))
+func NewCore(logger *custom_logger.Logger) zapcore.Core {
+	return &core{logger: logger}
+}
+
+// core turns zap entries into custom_logger entries
+type core struct {
+	logger *custom_logger.Logger
+	fields []zapcore.Field
+}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return c.logger.Enabled(levelFromZap(level))
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{logger: c.logger, fields: append(c.fields[:len(c.fields):len(c.fields)], fields...)}
+}
+
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	if entry.LoggerName != "" {
+		enc.Fields["logger"] = entry.LoggerName
+	}
+
+	out := custom_logger.Entry{
+		Time:    entry.Time,
+		Level:   levelFromZap(entry.Level),
+		Message: entry.Message,
+		Fields:  enc.Fields,
+	}
+	if entry.Caller.Defined {
+		out.Caller = entry.Caller.TrimmedPath()
+		out.Function = entry.Caller.Function
+	}
+	return c.logger.LogEntry(out)
+}
+
+func (c *core) Sync() error {
+	return nil
+}
+
+// levelFromZap maps a zap level onto the nearest LogLevel. Panic and fatal
+// levels map to ERROR; zap itself panics or exits after writing them.
+func levelFromZap(level zapcore.Level) custom_logger.LogLevel {
+	switch {
+	case level < zapcore.InfoLevel:
+		return custom_logger.DEBUG
+	case level < zapcore.WarnLevel:
+		return custom_logger.INFO
+	case level < zapcore.ErrorLevel:
+		return custom_logger.WARNING
+	default:
+		return custom_logger.ERROR
+	}
+}